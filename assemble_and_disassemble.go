@@ -1,6 +1,9 @@
 package jwtcore
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // Assemble takes multiple JSON Web strings and merges them into a single one.
 func Assemble(tokens ...string) string {
@@ -11,3 +14,18 @@ func Assemble(tokens ...string) string {
 func Disassemble(token string) []string {
 	return strings.Split(token, ".")
 }
+
+// DisassembleN works like Disassemble, but fails with ErrTooManySegments if the token has more than n segments.
+// Segments are counted before the token is split, so a token made of many separators does not cause a large
+// allocation.
+func DisassembleN(token string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid number of segments: %d", n)
+	}
+
+	if count := strings.Count(token, ".") + 1; count > n {
+		return nil, fmt.Errorf("%w: expected at most %d, got %d", ErrTooManySegments, n, count)
+	}
+
+	return Disassemble(token), nil
+}
@@ -1,6 +1,7 @@
 package jwtcore_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -61,3 +62,48 @@ func TestDisassemble(t *testing.T) {
 		})
 	}
 }
+
+func TestDisassembleN(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		token string
+		n     int
+
+		expect    []string
+		expectErr error
+	}{
+		{
+			name: "ok",
+
+			token: "foo.bar.baz",
+			n:     3,
+
+			expect: []string{"foo", "bar", "baz"},
+		},
+		{
+			name: "fewer segments",
+
+			token: "foo.bar",
+			n:     3,
+
+			expect: []string{"foo", "bar"},
+		},
+		{
+			name: "too many segments",
+
+			token: strings.Repeat(".", 1000),
+			n:     5,
+
+			expectErr: jwtcore.ErrTooManySegments,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			parts, err := jwtcore.DisassembleN(testCase.token, testCase.n)
+			require.ErrorIs(t, err, testCase.expectErr)
+			require.Equal(t, testCase.expect, parts)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package jwtcore
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+)
+
+var (
+	ErrExpired         = errors.New("token is expired")
+	ErrNotYetValid     = errors.New("token is not valid yet")
+	ErrIssuedInFuture  = errors.New("token is issued in the future")
+	ErrInvalidIssuer   = errors.New("invalid issuer")
+	ErrInvalidAudience = errors.New("invalid audience")
+)
+
+// ClaimsConfig configures the validation of registered claims.
+type ClaimsConfig struct {
+	// Leeway is the clock skew tolerated when checking the "exp", "nbf" and "iat" claims.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.4
+	//
+	// Implementers MAY provide for some small leeway, usually no more than
+	// a few minutes, to account for clock skew.
+	Leeway time.Duration
+	// Issuers is the list of accepted issuers. When set, the "iss" claim must match one of them.
+	Issuers []string
+	// Audiences is the list of audiences the recipient identifies with. When set, the "aud" claim must contain
+	// at least one of them.
+	Audiences []string
+	// Clock returns the current time. It defaults to time.Now.
+	Clock func() time.Time
+}
+
+// ValidateClaims checks the registered claims of a JWT. Time based claims are only checked when present. A nil
+// configuration uses the defaults.
+//
+// The returned error wraps ErrExpired, ErrNotYetValid, ErrIssuedInFuture, ErrInvalidIssuer or ErrInvalidAudience,
+// depending on the claim that failed.
+func ValidateClaims(claims *jwa.Claims, config *ClaimsConfig) error {
+	if config == nil {
+		config = &ClaimsConfig{}
+	}
+
+	now := time.Now()
+	if config.Clock != nil {
+		now = config.Clock()
+	}
+
+	// The processing of the "exp" claim requires that the current date/time MUST be before the expiration
+	// date/time listed in the "exp" claim.
+	if claims.Exp != 0 {
+		// The leeway is applied to the current time, so it cannot overflow a claim close to the latest representable time.
+		exp := claims.Exp.Time()
+		if !now.Add(-config.Leeway).Before(exp) {
+			return fmt.Errorf("%w: expired at %s", ErrExpired, exp.UTC().Format(time.RFC3339))
+		}
+	}
+
+	// The processing of the "nbf" claim requires that the current date/time MUST be after or equal to the
+	// not-before date/time listed in the "nbf" claim.
+	if claims.Nbf != 0 {
+		nbf := claims.Nbf.Time()
+		if now.Add(config.Leeway).Before(nbf) {
+			return fmt.Errorf("%w: valid from %s", ErrNotYetValid, nbf.UTC().Format(time.RFC3339))
+		}
+	}
+
+	if claims.Iat != 0 {
+		iat := claims.Iat.Time()
+		if now.Add(config.Leeway).Before(iat) {
+			return fmt.Errorf("%w: issued at %s", ErrIssuedInFuture, iat.UTC().Format(time.RFC3339))
+		}
+	}
+
+	if len(config.Issuers) > 0 && !slices.Contains(config.Issuers, claims.Iss) {
+		return fmt.Errorf("%w: %q", ErrInvalidIssuer, claims.Iss)
+	}
+
+	// If the principal processing the claim does not identify itself with a value in the "aud" claim when this
+	// claim is present, then the JWT MUST be rejected.
+	if len(config.Audiences) > 0 && !slices.ContainsFunc(config.Audiences, claims.Aud.Contains) {
+		return fmt.Errorf("%w: %q", ErrInvalidAudience, []string(claims.Aud))
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package jwtcore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/a-novel-kit/jwt-core/internal/members"
+	"github.com/a-novel-kit/jwt-core/jwa"
+)
+
+var (
+	ErrMissingClaim    = errors.New("missing claim")
+	ErrRegisteredClaim = errors.New("registered claim")
+)
+
+// registeredClaims lists the members of jwa.Claims.
+var registeredClaims = []string{"iss", "sub", "aud", "exp", "nbf", "iat", "jti"}
+
+// ClaimsSet holds the registered claims of a JWT, along with any other member of the claims set.
+//
+// https://datatracker.ietf.org/doc/html/rfc7519#section-4
+//
+// There are three classes of JWT Claim Names: Registered Claim Names,
+// Public Claim Names, and Private Claim Names.
+//
+// Members that are not registered claims are kept as raw JSON in Extra, so a claims set can be decoded and encoded
+// again without losing the claims it does not understand.
+type ClaimsSet struct {
+	jwa.Claims
+
+	// Extra holds the public and private claims, indexed by name.
+	Extra map[string]json.RawMessage
+}
+
+func (claims ClaimsSet) MarshalJSON() ([]byte, error) {
+	serialized, err := members.Marshal(claims.Claims, claims.Extra, registeredClaims)
+	if errors.Is(err, members.ErrModeledMember) {
+		return nil, fmt.Errorf("%w: %w", ErrRegisteredClaim, err)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("marshal claims: %w", err)
+	}
+
+	return serialized, nil
+}
+
+// UnmarshalJSON decodes the claims set. Claim names are case-sensitive: a name that only matches a registered
+// claim by its case, such as "Exp", is rejected with ErrRegisteredClaim.
+func (claims *ClaimsSet) UnmarshalJSON(data []byte) error {
+	var registered jwa.Claims
+
+	extra, err := members.Unmarshal(data, &registered, registeredClaims)
+	if errors.Is(err, members.ErrModeledMember) {
+		return fmt.Errorf("%w: %w", ErrRegisteredClaim, err)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unmarshal claims: %w", err)
+	}
+
+	claims.Claims = registered
+	claims.Extra = extra
+
+	return nil
+}
+
+// Has returns true if the claims set has an extra member with the given name.
+func (claims *ClaimsSet) Has(name string) bool {
+	_, ok := claims.Extra[name]
+
+	return ok
+}
+
+// Get decodes the value of an extra member into dst. It returns ErrMissingClaim if the member is not present.
+func (claims *ClaimsSet) Get(name string, dst any) error {
+	value, ok := claims.Extra[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrMissingClaim, name)
+	}
+
+	if err := json.Unmarshal(value, dst); err != nil {
+		return fmt.Errorf("unmarshal claim %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// GetString returns the value of an extra member, which must be a string.
+func (claims *ClaimsSet) GetString(name string) (string, error) {
+	var value string
+	if err := claims.Get(name, &value); err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// GetStrings returns the value of an extra member, which must be an array of strings.
+func (claims *ClaimsSet) GetStrings(name string) ([]string, error) {
+	var value []string
+	if err := claims.Get(name, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// GetBool returns the value of an extra member, which must be a boolean.
+func (claims *ClaimsSet) GetBool(name string) (bool, error) {
+	var value bool
+	if err := claims.Get(name, &value); err != nil {
+		return false, err
+	}
+
+	return value, nil
+}
+
+// GetNumber returns the value of an extra member, which must be a number.
+func (claims *ClaimsSet) GetNumber(name string) (float64, error) {
+	var value float64
+	if err := claims.Get(name, &value); err != nil {
+		return 0, err
+	}
+
+	return value, nil
+}
+
+// Set encodes a value as an extra member. Registered claims cannot be set this way, and must be set on the
+// embedded jwa.Claims instead.
+func (claims *ClaimsSet) Set(name string, value any) error {
+	if err := members.CheckExtra(name, registeredClaims); err != nil {
+		return fmt.Errorf("%w: %w", ErrRegisteredClaim, err)
+	}
+
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshal claim %q: %w", name, err)
+	}
+
+	if claims.Extra == nil {
+		claims.Extra = make(map[string]json.RawMessage)
+	}
+
+	claims.Extra[name] = serialized
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package jwtcore_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+)
+
+func TestClaimsSet(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		source := map[string]any{
+			"iss":    "issuer",
+			"aud":    []any{"audience", "other-audience"},
+			"exp":    float64(1700000000),
+			"tenant": "tenant-1",
+			"roles":  []any{"admin", "user"},
+			"admin":  true,
+			"quota":  float64(42),
+			"nested": map[string]any{"unknown": []any{float64(1), float64(2)}},
+		}
+
+		token, err := jwtcore.Encode(source)
+		require.NoError(t, err)
+
+		var claims jwtcore.ClaimsSet
+		require.NoError(t, jwtcore.Decode(token, &claims))
+
+		require.Equal(t, "issuer", claims.Iss)
+		require.Equal(t, jwa.Audience{"audience", "other-audience"}, claims.Aud)
+		require.Equal(t, jwa.NumericDate(1700000000), claims.Exp)
+		require.False(t, claims.Has("iss"))
+		require.True(t, claims.Has("nested"))
+
+		tenant, err := claims.GetString("tenant")
+		require.NoError(t, err)
+		require.Equal(t, "tenant-1", tenant)
+
+		roles, err := claims.GetStrings("roles")
+		require.NoError(t, err)
+		require.Equal(t, []string{"admin", "user"}, roles)
+
+		admin, err := claims.GetBool("admin")
+		require.NoError(t, err)
+		require.True(t, admin)
+
+		quota, err := claims.GetNumber("quota")
+		require.NoError(t, err)
+		require.InDelta(t, 42, quota, 0)
+
+		reEncoded, err := jwtcore.Encode(claims)
+		require.NoError(t, err)
+
+		var decoded map[string]any
+		require.NoError(t, jwtcore.Decode(reEncoded, &decoded))
+		require.Equal(t, source, decoded)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		claims := jwtcore.ClaimsSet{Claims: jwa.Claims{Sub: "user-1"}}
+		require.NoError(t, claims.Set("scope", "read write"))
+
+		serialized, err := json.Marshal(claims)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"sub":"user-1","scope":"read write"}`, string(serialized))
+	})
+
+	t.Run("set registered claim", func(t *testing.T) {
+		var claims jwtcore.ClaimsSet
+		require.ErrorIs(t, claims.Set("sub", "user-1"), jwtcore.ErrRegisteredClaim)
+		require.ErrorIs(t, claims.Set("Exp", 1), jwtcore.ErrRegisteredClaim)
+		require.ErrorIs(t, claims.Set("i\u017fs", "foo"), jwtcore.ErrRegisteredClaim)
+	})
+
+	t.Run("unmarshal case variant of registered claim", func(t *testing.T) {
+		var claims jwtcore.ClaimsSet
+		require.ErrorIs(t, json.Unmarshal([]byte(`{"Exp":1,"tenant":"t"}`), &claims), jwtcore.ErrRegisteredClaim)
+	})
+
+	t.Run("marshal registered claim in extra", func(t *testing.T) {
+		claims := jwtcore.ClaimsSet{Extra: map[string]json.RawMessage{"sub": json.RawMessage(`"user-1"`)}}
+
+		_, err := json.Marshal(claims)
+		require.ErrorIs(t, err, jwtcore.ErrRegisteredClaim)
+
+		claims = jwtcore.ClaimsSet{Extra: map[string]json.RawMessage{"SUB": json.RawMessage(`"user-1"`)}}
+
+		_, err = json.Marshal(claims)
+		require.ErrorIs(t, err, jwtcore.ErrRegisteredClaim)
+	})
+
+	t.Run("missing claim", func(t *testing.T) {
+		var claims jwtcore.ClaimsSet
+
+		_, err := claims.GetString("tenant")
+		require.ErrorIs(t, err, jwtcore.ErrMissingClaim)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		var claims jwtcore.ClaimsSet
+		require.NoError(t, claims.Set("tenant", 1))
+
+		_, err := claims.GetString("tenant")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,355 @@
+package jwtcore_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+)
+
+func TestValidateClaims(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	testCases := []struct {
+		name string
+
+		claims jwa.Claims
+		config jwtcore.ClaimsConfig
+
+		expect error
+	}{
+		{
+			name: "ok",
+
+			claims: jwa.Claims{
+				Iss: "issuer",
+				Aud: jwa.Audience{"audience"},
+				Exp: jwa.NewNumericDate(now.Add(time.Hour)),
+				Nbf: jwa.NewNumericDate(now.Add(-time.Hour)),
+				Iat: jwa.NewNumericDate(now.Add(-time.Hour)),
+			},
+			config: jwtcore.ClaimsConfig{
+				Issuers:   []string{"other-issuer", "issuer"},
+				Audiences: []string{"audience"},
+				Clock:     clock,
+			},
+		},
+		{
+			name: "no claims",
+
+			config: jwtcore.ClaimsConfig{Clock: clock},
+		},
+		{
+			name: "expired",
+
+			claims: jwa.Claims{Exp: jwa.NewNumericDate(now.Add(-time.Second))},
+			config: jwtcore.ClaimsConfig{Clock: clock},
+
+			expect: jwtcore.ErrExpired,
+		},
+		{
+			name: "expires now",
+
+			claims: jwa.Claims{Exp: jwa.NewNumericDate(now)},
+			config: jwtcore.ClaimsConfig{Clock: clock},
+
+			expect: jwtcore.ErrExpired,
+		},
+		{
+			name: "expired with fractional seconds",
+
+			claims: jwa.Claims{Exp: jwa.NumericDate(float64(now.Unix()) + 0.5)},
+			config: jwtcore.ClaimsConfig{Clock: func() time.Time { return now.Add(600 * time.Millisecond) }},
+
+			expect: jwtcore.ErrExpired,
+		},
+		{
+			name: "expired within leeway",
+
+			claims: jwa.Claims{Exp: jwa.NewNumericDate(now.Add(-time.Minute))},
+			config: jwtcore.ClaimsConfig{Clock: clock, Leeway: 2 * time.Minute},
+		},
+		{
+			name: "not yet valid",
+
+			claims: jwa.Claims{Nbf: jwa.NewNumericDate(now.Add(time.Second))},
+			config: jwtcore.ClaimsConfig{Clock: clock},
+
+			expect: jwtcore.ErrNotYetValid,
+		},
+		{
+			name: "valid from now",
+
+			claims: jwa.Claims{Nbf: jwa.NewNumericDate(now)},
+			config: jwtcore.ClaimsConfig{Clock: clock},
+		},
+		{
+			name: "not yet valid within leeway",
+
+			claims: jwa.Claims{Nbf: jwa.NewNumericDate(now.Add(time.Minute))},
+			config: jwtcore.ClaimsConfig{Clock: clock, Leeway: 2 * time.Minute},
+		},
+		{
+			name: "issued in the future",
+
+			claims: jwa.Claims{Iat: jwa.NewNumericDate(now.Add(time.Minute))},
+			config: jwtcore.ClaimsConfig{Clock: clock},
+
+			expect: jwtcore.ErrIssuedInFuture,
+		},
+		{
+			name: "issued in the future within leeway",
+
+			claims: jwa.Claims{Iat: jwa.NewNumericDate(now.Add(time.Minute))},
+			config: jwtcore.ClaimsConfig{Clock: clock, Leeway: 2 * time.Minute},
+		},
+		{
+			name: "not yet valid far in the future",
+
+			claims: jwa.Claims{Nbf: 1e300},
+			config: jwtcore.ClaimsConfig{Clock: clock},
+
+			expect: jwtcore.ErrNotYetValid,
+		},
+		{
+			name: "issued far in the future",
+
+			claims: jwa.Claims{Iat: 1e300},
+			config: jwtcore.ClaimsConfig{Clock: clock},
+
+			expect: jwtcore.ErrIssuedInFuture,
+		},
+		{
+			name: "expires far in the future",
+
+			claims: jwa.Claims{Exp: 1e300},
+			config: jwtcore.ClaimsConfig{Clock: clock, Leeway: time.Minute},
+		},
+		{
+			name: "expired far in the past",
+
+			claims: jwa.Claims{Exp: -1e300},
+			config: jwtcore.ClaimsConfig{Clock: clock, Leeway: time.Minute},
+
+			expect: jwtcore.ErrExpired,
+		},
+		{
+			name: "wrong issuer",
+
+			claims: jwa.Claims{Iss: "issuer"},
+			config: jwtcore.ClaimsConfig{Clock: clock, Issuers: []string{"other-issuer"}},
+
+			expect: jwtcore.ErrInvalidIssuer,
+		},
+		{
+			name: "missing issuer",
+
+			config: jwtcore.ClaimsConfig{Clock: clock, Issuers: []string{"issuer"}},
+
+			expect: jwtcore.ErrInvalidIssuer,
+		},
+		{
+			name: "wrong audience",
+
+			claims: jwa.Claims{Aud: jwa.Audience{"audience"}},
+			config: jwtcore.ClaimsConfig{Clock: clock, Audiences: []string{"other-audience"}},
+
+			expect: jwtcore.ErrInvalidAudience,
+		},
+		{
+			name: "multiple audiences",
+
+			claims: jwa.Claims{Aud: jwa.Audience{"audience", "other-audience"}},
+			config: jwtcore.ClaimsConfig{Clock: clock, Audiences: []string{"other-audience"}},
+		},
+		{
+			name: "missing audience",
+
+			config: jwtcore.ClaimsConfig{Clock: clock, Audiences: []string{"audience"}},
+
+			expect: jwtcore.ErrInvalidAudience,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := jwtcore.ValidateClaims(&testCase.claims, &testCase.config)
+			require.ErrorIs(t, err, testCase.expect)
+		})
+	}
+}
+
+func TestValidateClaimsDefaultConfig(t *testing.T) {
+	require.NoError(
+		t,
+		jwtcore.ValidateClaims(&jwa.Claims{Exp: jwa.NewNumericDate(time.Now().Add(time.Hour))}, nil),
+	)
+	require.ErrorIs(
+		t,
+		jwtcore.ValidateClaims(&jwa.Claims{Exp: jwa.NewNumericDate(time.Now().Add(-time.Hour))}, nil),
+		jwtcore.ErrExpired,
+	)
+}
+
+func TestAudience(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		json string
+
+		expect      jwa.Audience
+		expectJSON  string
+		expectError bool
+	}{
+		{
+			name:       "string",
+			json:       `{"aud":"audience"}`,
+			expect:     jwa.Audience{"audience"},
+			expectJSON: `{"aud":"audience"}`,
+		},
+		{
+			name:       "array",
+			json:       `{"aud":["audience","other-audience"]}`,
+			expect:     jwa.Audience{"audience", "other-audience"},
+			expectJSON: `{"aud":["audience","other-audience"]}`,
+		},
+		{
+			name:       "single value array",
+			json:       `{"aud":["audience"]}`,
+			expect:     jwa.Audience{"audience"},
+			expectJSON: `{"aud":"audience"}`,
+		},
+		{
+			name:       "missing",
+			json:       `{}`,
+			expectJSON: `{}`,
+		},
+		{
+			name:        "number",
+			json:        `{"aud":1}`,
+			expectError: true,
+		},
+		{
+			name:        "array of numbers",
+			json:        `{"aud":[1]}`,
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var claims jwa.Claims
+
+			err := jwtcore.Decode(base64.RawURLEncoding.EncodeToString([]byte(testCase.json)), &claims)
+			if testCase.expectError {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.expect, claims.Aud)
+
+			encoded, err := json.Marshal(claims)
+			require.NoError(t, err)
+			require.JSONEq(t, testCase.expectJSON, string(encoded))
+		})
+	}
+}
+
+func TestAudienceContains(t *testing.T) {
+	audience := jwa.Audience{"audience", "other-audience"}
+
+	require.True(t, audience.Contains("other-audience"))
+	require.False(t, audience.Contains("Audience"))
+	require.False(t, jwa.Audience(nil).Contains(""))
+}
+
+func TestNumericDate(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		json string
+
+		expect      time.Time
+		expectJSON  string
+		expectError bool
+	}{
+		{
+			name:       "integer",
+			json:       `{"exp":1700000000}`,
+			expect:     time.Unix(1700000000, 0),
+			expectJSON: `{"exp":1700000000}`,
+		},
+		{
+			name:       "fractional",
+			json:       `{"exp":1700000000.5}`,
+			expect:     time.Unix(1700000000, int64(500*time.Millisecond)),
+			expectJSON: `{"exp":1700000000.5}`,
+		},
+		{
+			name:        "string",
+			json:        `{"exp":"1700000000"}`,
+			expectError: true,
+		},
+		{
+			name:        "too large",
+			json:        `{"exp":1e300}`,
+			expectError: true,
+		},
+		{
+			name:        "too small",
+			json:        `{"exp":-1e300}`,
+			expectError: true,
+		},
+		{
+			name:        "overflows float",
+			json:        `{"exp":1e400}`,
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var claims jwa.Claims
+
+			err := jwtcore.Decode(base64.RawURLEncoding.EncodeToString([]byte(testCase.json)), &claims)
+			if testCase.expectError {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, testCase.expect.Equal(claims.Exp.Time()))
+
+			encoded, err := json.Marshal(claims)
+			require.NoError(t, err)
+			require.JSONEq(t, testCase.expectJSON, string(encoded))
+		})
+	}
+
+	t.Run("NewNumericDate", func(t *testing.T) {
+		date := jwa.NewNumericDate(time.Unix(1700000000, int64(900*time.Millisecond)))
+		require.Equal(t, jwa.NumericDate(1700000000), date)
+		require.True(t, time.Unix(1700000000, 0).Equal(date.Time()))
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		latest := jwa.NumericDate(1e300).Time()
+		earliest := jwa.NumericDate(-1e300).Time()
+
+		require.True(t, latest.After(time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)))
+		require.True(t, earliest.Before(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)))
+		require.True(t, latest.Equal(jwa.NumericDate(math.Inf(1)).Time()))
+		require.True(t, earliest.Equal(jwa.NumericDate(math.Inf(-1)).Time()))
+		require.True(t, jwa.NumericDate(math.NaN()).Time().IsZero())
+	})
+}
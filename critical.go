@@ -0,0 +1,87 @@
+package jwtcore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrUnsupportedCritical = errors.New("unsupported critical header parameter")
+	ErrInvalidCritical     = errors.New("invalid critical header parameter")
+)
+
+// registeredHeaders lists the header parameters registered for JWS and JWE. They must not appear in the "crit"
+// header.
+var registeredHeaders = []string{
+	// https://datatracker.ietf.org/doc/html/rfc7515#section-4.1
+	"alg", "jku", "jwk", "kid", "x5u", "x5c", "x5t", "x5t#S256", "typ", "cty", "crit",
+	// https://datatracker.ietf.org/doc/html/rfc7516#section-4.1
+	"enc", "zip",
+	// https://datatracker.ietf.org/doc/html/rfc7518#section-4.6.1
+	"epk", "apu", "apv",
+	// https://datatracker.ietf.org/doc/html/rfc7518#section-4.7.1
+	"iv", "tag",
+	// https://datatracker.ietf.org/doc/html/rfc7518#section-4.8.1
+	"p2s", "p2c",
+}
+
+// CheckCritical validates the "crit" parameter of an encoded protected header. Supported lists the extension
+// header parameters understood by the recipient.
+//
+// https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.11
+//
+// The "crit" (critical) Header Parameter indicates that extensions to
+// this specification and/or [JWA] are being used that MUST be
+// understood and processed. Its value is an array listing the Header
+// Parameter names present in the JOSE Header that use those extensions.
+// If any of the listed extension Header Parameters are not understood
+// and supported by the recipient, then the JWS is invalid. Producers
+// MUST NOT include Header Parameter names defined by this specification
+// or [JWA] for use with JWS, duplicate names, or names that do not
+// occur as Header Parameter names within the JOSE Header in the "crit"
+// list. Producers MUST NOT use the empty list "[]" as the "crit"
+// value.
+//
+// Since "crit" must be integrity protected, the listed parameters must also be present in the protected header.
+func CheckCritical(protected string, supported []string) error {
+	var header map[string]json.RawMessage
+	if err := DecodeStrict(protected, &header); err != nil {
+		return fmt.Errorf("decode protected header: %w", err)
+	}
+
+	rawCritical, ok := header["crit"]
+	if !ok {
+		return nil
+	}
+
+	var critical []string
+	if err := json.Unmarshal(rawCritical, &critical); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCritical, err)
+	}
+
+	if len(critical) == 0 {
+		return fmt.Errorf("%w: crit must not be empty", ErrInvalidCritical)
+	}
+
+	for i, name := range critical {
+		if slices.Contains(critical[:i], name) {
+			return fmt.Errorf("%w: duplicate parameter %q", ErrInvalidCritical, name)
+		}
+
+		if slices.Contains(registeredHeaders, name) {
+			return fmt.Errorf("%w: registered parameter %q", ErrInvalidCritical, name)
+		}
+
+		if _, ok := header[name]; !ok {
+			return fmt.Errorf("%w: parameter %q is not present in the protected header", ErrInvalidCritical, name)
+		}
+
+		if !slices.Contains(supported, name) {
+			return fmt.Errorf("%w: %q", ErrUnsupportedCritical, name)
+		}
+	}
+
+	return nil
+}
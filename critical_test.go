@@ -0,0 +1,73 @@
+package jwtcore_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+)
+
+func TestCheckCritical(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		header    map[string]any
+		supported []string
+
+		expect error
+	}{
+		{
+			name:   "no crit",
+			header: map[string]any{"alg": "HS256"},
+		},
+		{
+			name:      "supported",
+			header:    map[string]any{"alg": "HS256", "crit": []string{"exp"}, "exp": 1363284000},
+			supported: []string{"exp"},
+		},
+		{
+			name:   "unsupported",
+			header: map[string]any{"alg": "HS256", "crit": []string{"exp"}, "exp": 1363284000},
+			expect: jwtcore.ErrUnsupportedCritical,
+		},
+		{
+			name:      "registered parameter",
+			header:    map[string]any{"alg": "HS256", "crit": []string{"alg"}},
+			supported: []string{"alg"},
+			expect:    jwtcore.ErrInvalidCritical,
+		},
+		{
+			name:      "missing parameter",
+			header:    map[string]any{"alg": "HS256", "crit": []string{"exp"}},
+			supported: []string{"exp"},
+			expect:    jwtcore.ErrInvalidCritical,
+		},
+		{
+			name:      "duplicate parameter",
+			header:    map[string]any{"alg": "HS256", "crit": []string{"exp", "exp"}, "exp": 1363284000},
+			supported: []string{"exp"},
+			expect:    jwtcore.ErrInvalidCritical,
+		},
+		{
+			name:   "empty list",
+			header: map[string]any{"alg": "HS256", "crit": []string{}},
+			expect: jwtcore.ErrInvalidCritical,
+		},
+		{
+			name:   "not a list",
+			header: map[string]any{"alg": "HS256", "crit": "exp", "exp": 1363284000},
+			expect: jwtcore.ErrInvalidCritical,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			protected, err := jwtcore.Encode(testCase.header)
+			require.NoError(t, err)
+
+			err = jwtcore.CheckCritical(protected, testCase.supported)
+			require.ErrorIs(t, err, testCase.expect)
+		})
+	}
+}
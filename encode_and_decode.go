@@ -1,9 +1,21 @@
 package jwtcore
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
+
+	"github.com/a-novel-kit/jwt-core/internal/members"
+)
+
+var (
+	ErrNonCanonicalEncoding = errors.New("non canonical base64url encoding")
+	ErrDuplicateMember      = errors.New("duplicate JSON member")
+	ErrTrailingData         = errors.New("trailing data after JSON value")
 )
 
 // Encode takes a payload and returns a JSON Web string.
@@ -29,3 +41,111 @@ func Decode(token string, payload interface{}) error {
 
 	return nil
 }
+
+// DecodeSegment decodes a base64url segment, only accepting its canonical representation: no padding, no line
+// breaks, and no non-zero trailing bits. Without those checks, several distinct segments would decode to the same
+// value.
+//
+// https://datatracker.ietf.org/doc/html/rfc7515#section-2
+//
+// Base64 encoding using the URL- and filename-safe character set
+// defined in Section 5 of RFC 4648 [RFC4648], with all trailing '='
+// characters omitted (as permitted by Section 3.2) and without the
+// inclusion of any line breaks, whitespace, or other additional
+// characters.
+func DecodeSegment(segment string) ([]byte, error) {
+	// Line breaks are ignored by the base64 decoder, even in strict mode.
+	if strings.ContainsAny(segment, "\r\n") {
+		return nil, fmt.Errorf("%w: unexpected line break", ErrNonCanonicalEncoding)
+	}
+
+	decoded, err := base64.RawURLEncoding.Strict().DecodeString(segment)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNonCanonicalEncoding, err)
+	}
+
+	return decoded, nil
+}
+
+// DecodeStrict works like Decode, but rejects ambiguous inputs: non-canonical base64url (see DecodeSegment),
+// JSON objects with duplicate member names, and trailing data after the JSON value.
+//
+// https://datatracker.ietf.org/doc/html/rfc7515#section-5.2
+//
+// The resulting JOSE Header MUST NOT contain duplicate Header Parameter
+// names.
+//
+// Since encoding/json matches member names to struct fields case-insensitively, top-level member names that only
+// differ by their case are also considered duplicates, as they may collide with the same header parameter or claim.
+// Nested objects only reject exact duplicates.
+func DecodeStrict(token string, payload interface{}) error {
+	decoded, err := DecodeSegment(token)
+	if err != nil {
+		return fmt.Errorf("decode token: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(decoded))
+	if err = checkDuplicateMembers(decoder, true); err != nil {
+		return fmt.Errorf("read payload: %w", err)
+	}
+
+	if _, err = decoder.Token(); !errors.Is(err, io.EOF) {
+		return ErrTrailingData
+	}
+
+	if err = json.Unmarshal(decoded, payload); err != nil {
+		return fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	return nil
+}
+
+// checkDuplicateMembers reads the next JSON value from the decoder, and makes sure none of its objects, at any
+// depth, has duplicate member names. If fold is set, the member names of the value itself are compared the way
+// encoding/json matches them to struct fields.
+func checkDuplicateMembers(decoder *json.Decoder, fold bool) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		names := make(map[string]struct{})
+
+		for decoder.More() {
+			name, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+
+			key := name.(string)
+			if fold {
+				key = members.Fold(key)
+			}
+
+			if _, ok := names[key]; ok {
+				return fmt.Errorf("%w: %q", ErrDuplicateMember, name)
+			}
+
+			names[key] = struct{}{}
+
+			if err = checkDuplicateMembers(decoder, false); err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for decoder.More() {
+			if err = checkDuplicateMembers(decoder, false); err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+
+	// Consume the closing delimiter.
+	_, err = decoder.Token()
+
+	return err
+}
@@ -1,11 +1,13 @@
 package jwtcore_test
 
 import (
+	"encoding/base64"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
 )
 
 func TestEncodeAndDecode(t *testing.T) {
@@ -23,3 +25,130 @@ func TestEncodeAndDecode(t *testing.T) {
 
 	require.Equal(t, payload, decodedPayload)
 }
+
+func TestDecodeStrict(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	testCases := []struct {
+		name string
+
+		token string
+
+		expect    map[string]any
+		expectErr error
+	}{
+		{
+			name:   "ok",
+			token:  encode(`{"alg":"HS256","crit":["exp"],"nested":{"alg":"none"}}`),
+			expect: map[string]any{"alg": "HS256", "crit": []any{"exp"}, "nested": map[string]any{"alg": "none"}},
+		},
+		{
+			name:   "distinct non-ASCII members",
+			token:  encode(`{"iss":"good","ißs":"other"}`),
+			expect: map[string]any{"iss": "good", "ißs": "other"},
+		},
+		{
+			name:   "nested members differing by case",
+			token:  encode(`{"ctx":{"userId":1,"userid":2}}`),
+			expect: map[string]any{"ctx": map[string]any{"userId": float64(1), "userid": float64(2)}},
+		},
+		{
+			name:   "trailing whitespace",
+			token:  encode("{\"alg\":\"HS256\"}\n"),
+			expect: map[string]any{"alg": "HS256"},
+		},
+		{
+			name:      "duplicate member",
+			token:     encode(`{"alg":"HS256","alg":"none"}`),
+			expectErr: jwtcore.ErrDuplicateMember,
+		},
+		{
+			name:      "duplicate member with different case",
+			token:     encode(`{"alg":"HS256","ALG":"none"}`),
+			expectErr: jwtcore.ErrDuplicateMember,
+		},
+		{
+			name:      "duplicate member with escaped name",
+			token:     encode(`{"alg":"HS256","\u0061lg":"none"}`),
+			expectErr: jwtcore.ErrDuplicateMember,
+		},
+		{
+			// U+017F LATIN SMALL LETTER LONG S folds to "s", like encoding/json does when matching struct fields.
+			name:      "duplicate member with long s",
+			token:     encode(`{"iss":"good","iſs":"evil"}`),
+			expectErr: jwtcore.ErrDuplicateMember,
+		},
+		{
+			// U+212A KELVIN SIGN folds to "k".
+			name:      "duplicate member with kelvin sign",
+			token:     encode(`{"kid":"good","Kid":"evil"}`),
+			expectErr: jwtcore.ErrDuplicateMember,
+		},
+		{
+			name:      "nested duplicate member",
+			token:     encode(`{"jwk":[{"kty":"EC","kty":"RSA"}]}`),
+			expectErr: jwtcore.ErrDuplicateMember,
+		},
+		{
+			name:      "trailing data",
+			token:     encode(`{"alg":"HS256"}{"alg":"none"}`),
+			expectErr: jwtcore.ErrTrailingData,
+		},
+		{
+			name:      "padding",
+			token:     base64.URLEncoding.EncodeToString([]byte(`{"alg":"none"}`)),
+			expectErr: jwtcore.ErrNonCanonicalEncoding,
+		},
+		{
+			// "e30" is the canonical encoding of "{}". The last character carries 2 unused bits, set here.
+			name:      "non-zero trailing bits",
+			token:     "e31",
+			expectErr: jwtcore.ErrNonCanonicalEncoding,
+		},
+		{
+			name:      "line break",
+			token:     "e3\n0",
+			expectErr: jwtcore.ErrNonCanonicalEncoding,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var decoded map[string]any
+
+			err := jwtcore.DecodeStrict(testCase.token, &decoded)
+			require.ErrorIs(t, err, testCase.expectErr)
+
+			if testCase.expectErr == nil {
+				require.Equal(t, testCase.expect, decoded)
+			}
+		})
+	}
+}
+
+func TestDecodeSegment(t *testing.T) {
+	decoded, err := jwtcore.DecodeSegment("e30")
+	require.NoError(t, err)
+	require.Equal(t, []byte("{}"), decoded)
+
+	// The permissive decoder accepts the non-canonical form.
+	decoded, err = base64.RawURLEncoding.DecodeString("e31")
+	require.NoError(t, err)
+	require.Equal(t, []byte("{}"), decoded)
+
+	_, err = jwtcore.DecodeSegment("e31")
+	require.ErrorIs(t, err, jwtcore.ErrNonCanonicalEncoding)
+}
+
+func TestDecodeStrictFolding(t *testing.T) {
+	// encoding/json matches "iſs" to the "iss" field, so it must be reported as a duplicate.
+	token := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"good","iſs":"evil","sub":"user"}`))
+
+	var claims jwa.Claims
+	require.ErrorIs(t, jwtcore.DecodeStrict(token, &claims), jwtcore.ErrDuplicateMember)
+
+	var header jwa.JWH
+	require.ErrorIs(t, jwtcore.DecodeStrict(token, &header), jwtcore.ErrDuplicateMember)
+}
@@ -0,0 +1,61 @@
+package jwtcore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrDuplicateHeader = errors.New("duplicate header parameter")
+
+// MergeHeaders combines multiple JOSE headers into dst. Nil headers are ignored.
+//
+// https://datatracker.ietf.org/doc/html/rfc7515#section-7.2.1
+//
+// The Header Parameter values used when creating or validating
+// individual signature or MAC values are the union of the two sets of
+// Header Parameter values that may be present: (1) the JWS Protected
+// Header represented in the "protected" member of the signature/MAC's
+// array element, and (2) the JWS Unprotected Header in the "header"
+// member of the signature/MAC's array element. The union of these sets
+// of Header Parameters comprises the JOSE Header. The Header Parameter
+// names in the two locations MUST be disjoint.
+func MergeHeaders(dst any, headers ...any) error {
+	merged := make(map[string]json.RawMessage)
+
+	for _, header := range headers {
+		if header == nil {
+			continue
+		}
+
+		serialized, err := json.Marshal(header)
+		if err != nil {
+			return fmt.Errorf("marshal header: %w", err)
+		}
+
+		// Nil pointers are serialized as "null", which results in an empty map.
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(serialized, &fields); err != nil {
+			return fmt.Errorf("unmarshal header: %w", err)
+		}
+
+		for name, value := range fields {
+			if _, ok := merged[name]; ok {
+				return fmt.Errorf("%w: %q", ErrDuplicateHeader, name)
+			}
+
+			merged[name] = value
+		}
+	}
+
+	serialized, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("marshal merged header: %w", err)
+	}
+
+	if err := json.Unmarshal(serialized, dst); err != nil {
+		return fmt.Errorf("unmarshal merged header: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package jwtcore_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+)
+
+func TestMergeHeaders(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		headers []any
+
+		expect    jwa.JWH
+		expectErr error
+	}{
+		{
+			name: "ok",
+
+			headers: []any{
+				&jwa.JWH{Alg: jwa.ES256},
+				&jwa.JWH{KID: "key-1"},
+			},
+
+			expect: jwa.JWH{Alg: jwa.ES256, KID: "key-1"},
+		},
+		{
+			name: "nil headers",
+
+			headers: []any{
+				nil,
+				(*jwa.JWH)(nil),
+				&jwa.JWH{KID: "key-1"},
+			},
+
+			expect: jwa.JWH{KID: "key-1"},
+		},
+		{
+			name: "private parameters",
+
+			headers: []any{
+				&jwa.JWH{Alg: jwa.ES256, Extra: map[string]json.RawMessage{"tenant": json.RawMessage(`"tenant-1"`)}},
+				&jwa.JWH{KID: "key-1", Extra: map[string]json.RawMessage{"trace": json.RawMessage(`"trace-1"`)}},
+			},
+
+			expect: jwa.JWH{
+				Alg: jwa.ES256,
+				KID: "key-1",
+				Extra: map[string]json.RawMessage{
+					"tenant": json.RawMessage(`"tenant-1"`),
+					"trace":  json.RawMessage(`"trace-1"`),
+				},
+			},
+		},
+		{
+			name: "duplicate private parameter",
+
+			headers: []any{
+				&jwa.JWH{Extra: map[string]json.RawMessage{"tenant": json.RawMessage(`"tenant-1"`)}},
+				&jwa.JWH{Extra: map[string]json.RawMessage{"tenant": json.RawMessage(`"tenant-2"`)}},
+			},
+
+			expectErr: jwtcore.ErrDuplicateHeader,
+		},
+		{
+			name: "duplicate parameter",
+
+			headers: []any{
+				&jwa.JWH{Alg: jwa.ES256, KID: "key-1"},
+				&jwa.JWH{KID: "key-2"},
+			},
+
+			expectErr: jwtcore.ErrDuplicateHeader,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var header jwa.JWH
+
+			err := jwtcore.MergeHeaders(&header, testCase.headers...)
+			require.ErrorIs(t, err, testCase.expectErr)
+
+			if testCase.expectErr == nil {
+				require.Equal(t, testCase.expect, header)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Package members splits JSON objects between the members modeled by a struct, and extra members kept as raw JSON.
+//
+// JOSE member names are case-sensitive, while encoding/json matches struct fields case-insensitively. To prevent
+// a member from being read as a modeled one under a different name, any name that folds onto a modeled name
+// without being equal to it is rejected.
+package members
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"unicode"
+)
+
+var ErrModeledMember = errors.New("member name conflicts with a modeled member")
+
+// Fold returns a key such that two member names have the same key if, and only if, encoding/json matches them to
+// the same struct field. It uses simple Unicode folding, so "iss" also matches "iſs" (U+017F LATIN SMALL LETTER
+// LONG S).
+func Fold(name string) string {
+	folded := make([]rune, 0, len(name))
+
+	for _, r := range name {
+		// Map the rune to the smallest rune of its fold set.
+		for {
+			next := unicode.SimpleFold(r)
+			if next <= r {
+				r = next
+
+				break
+			}
+
+			r = next
+		}
+
+		folded = append(folded, r)
+	}
+
+	return string(folded)
+}
+
+// CheckExtra makes sure an extra member name does not conflict with a modeled one, either because it is equal to
+// it, or because encoding/json would match it to the same field.
+func CheckExtra(name string, modeled []string) error {
+	folded := Fold(name)
+
+	for _, modeledName := range modeled {
+		if Fold(modeledName) == folded {
+			return fmt.Errorf("%w: %q must be set on the %q field", ErrModeledMember, name, modeledName)
+		}
+	}
+
+	return nil
+}
+
+// Marshal serializes value, then adds the extra members to the resulting JSON object.
+func Marshal(value any, extra map[string]json.RawMessage, modeled []string) ([]byte, error) {
+	serialized, err := json.Marshal(value)
+	if err != nil || len(extra) == 0 {
+		return serialized, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(serialized, &fields); err != nil {
+		return nil, err
+	}
+
+	for name, member := range extra {
+		if err = CheckExtra(name, modeled); err != nil {
+			return nil, err
+		}
+
+		fields[name] = member
+	}
+
+	return json.Marshal(fields)
+}
+
+// Unmarshal decodes the modeled members of a JSON object into value, and returns the other members. It returns a
+// nil map if there is no extra member.
+//
+// Member names that only match a modeled name by folding are rejected, instead of being silently decoded into
+// the modeled field.
+func Unmarshal(data []byte, value any, modeled []string) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	extra := make(map[string]json.RawMessage)
+
+	for name, member := range fields {
+		if slices.Contains(modeled, name) {
+			continue
+		}
+
+		if err := CheckExtra(name, modeled); err != nil {
+			return nil, err
+		}
+
+		extra[name] = member
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return nil, err
+	}
+
+	if len(extra) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	return extra, nil
+}
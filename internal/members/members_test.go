@@ -0,0 +1,155 @@
+package members_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/jwt-core/internal/members"
+)
+
+type modeledValue struct {
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+var modeledNames = []string{"alg", "kid"}
+
+func TestFold(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		a, b string
+
+		expect bool
+	}{
+		{
+			name:   "equal",
+			a:      "alg",
+			b:      "alg",
+			expect: true,
+		},
+		{
+			name:   "ASCII case",
+			a:      "alg",
+			b:      "ALG",
+			expect: true,
+		},
+		{
+			name:   "long s",
+			a:      "iss",
+			b:      "i\u017fs",
+			expect: true,
+		},
+		{
+			name:   "kelvin sign",
+			a:      "kid",
+			b:      "\u212aid",
+			expect: true,
+		},
+		{
+			name: "sharp s",
+			a:    "iss",
+			b:    "iß",
+		},
+		{
+			name: "different",
+			a:    "alg",
+			b:    "enc",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expect, members.Fold(testCase.a) == members.Fold(testCase.b))
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		extra map[string]json.RawMessage
+
+		expect    string
+		expectErr error
+	}{
+		{
+			name:   "no extra",
+			expect: `{"alg":"HS256"}`,
+		},
+		{
+			name:   "extra",
+			extra:  map[string]json.RawMessage{"tenant": json.RawMessage(`"foo"`)},
+			expect: `{"alg":"HS256","tenant":"foo"}`,
+		},
+		{
+			name:      "modeled name",
+			extra:     map[string]json.RawMessage{"alg": json.RawMessage(`"none"`)},
+			expectErr: members.ErrModeledMember,
+		},
+		{
+			name:      "case variant of modeled name",
+			extra:     map[string]json.RawMessage{"ALG": json.RawMessage(`"none"`)},
+			expectErr: members.ErrModeledMember,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			serialized, err := members.Marshal(modeledValue{Alg: "HS256"}, testCase.extra, modeledNames)
+			require.ErrorIs(t, err, testCase.expectErr)
+
+			if testCase.expectErr == nil {
+				require.JSONEq(t, testCase.expect, string(serialized))
+			}
+		})
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		data string
+
+		expect      modeledValue
+		expectExtra map[string]json.RawMessage
+		expectErr   error
+	}{
+		{
+			name:   "no extra",
+			data:   `{"alg":"HS256"}`,
+			expect: modeledValue{Alg: "HS256"},
+		},
+		{
+			name:        "extra",
+			data:        `{"alg":"HS256","tenant":"foo"}`,
+			expect:      modeledValue{Alg: "HS256"},
+			expectExtra: map[string]json.RawMessage{"tenant": json.RawMessage(`"foo"`)},
+		},
+		{
+			name:      "case variant of modeled name",
+			data:      `{"ALG":"HS256"}`,
+			expectErr: members.ErrModeledMember,
+		},
+		{
+			name:      "kelvin sign",
+			data:      "{\"\u212aid\":\"foo\"}",
+			expectErr: members.ErrModeledMember,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var value modeledValue
+
+			extra, err := members.Unmarshal([]byte(testCase.data), &value, modeledNames)
+			require.ErrorIs(t, err, testCase.expectErr)
+			require.Equal(t, testCase.expect, value)
+			require.Equal(t, testCase.expectExtra, extra)
+		})
+	}
+}
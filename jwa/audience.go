@@ -0,0 +1,58 @@
+package jwa
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Audience is the value of the "aud" claim.
+//
+// https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.3
+//
+// In the general case, the "aud" value is an array of case-
+// sensitive strings, each containing a StringOrURI value. In the
+// special case when the JWT has one audience, the "aud" value MAY be a
+// single case-sensitive string containing a StringOrURI value.
+//
+// Audience unmarshals both forms. A single audience is marshaled as a string, for compatibility with
+// implementations that do not support the array form.
+type Audience []string
+
+// Contains returns true if the audience lists the given value.
+func (audience Audience) Contains(value string) bool {
+	return slices.Contains(audience, value)
+}
+
+func (audience Audience) MarshalJSON() ([]byte, error) {
+	if len(audience) == 1 {
+		return json.Marshal(audience[0])
+	}
+
+	return json.Marshal([]string(audience))
+}
+
+func (audience *Audience) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch typed := value.(type) {
+	case nil:
+		*audience = nil
+	case string:
+		*audience = Audience{typed}
+	case []any:
+		var values []string
+		if err := json.Unmarshal(data, &values); err != nil {
+			return fmt.Errorf("unmarshal audience: %w", err)
+		}
+
+		*audience = values
+	default:
+		return fmt.Errorf("unmarshal audience: expected a string or an array of strings, got %s", data)
+	}
+
+	return nil
+}
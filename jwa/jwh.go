@@ -1,5 +1,11 @@
 package jwa
 
+import (
+	"encoding/json"
+
+	"github.com/a-novel-kit/jwt-core/internal/members"
+)
+
 // Typ represents the "typ" field of a JWT header.
 //
 // https://datatracker.ietf.org/doc/html/rfc7519#section-5.1
@@ -258,6 +264,24 @@ type JWH struct {
 	// }
 	Crit []string `json:"crit,omitempty"`
 
+	// B64 (Base64url-Encode Payload) Header Parameter.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc7797#section-3
+	//
+	// The "b64" (base64url-encode payload) Header Parameter determines
+	// whether the payload is represented in the JWS and the JWS Signing
+	// Input as ASCII(BASE64URL(JWS Payload)) or as the JWS Payload value
+	// itself with no encoding performed. When the "b64" value is "false",
+	// the payload is represented simply as the JWS Payload value;
+	// otherwise, it is represented as ASCII(BASE64URL(JWS Payload)). The
+	// "b64" value is a JSON boolean, with a default value of "true".
+	//
+	// https://datatracker.ietf.org/doc/html/rfc7797#section-6
+	//
+	// When the "b64" value is "false", the "crit" Header Parameter MUST be
+	// present, and its value MUST include "b64".
+	B64 *bool `json:"b64,omitempty"`
+
 	// https://datatracker.ietf.org/doc/html/rfc7519#section-5.3
 	//
 	// In some applications using encrypted JWTs, it is useful to have an
@@ -327,7 +351,52 @@ type JWH struct {
 	// single case-sensitive string containing a StringOrURI value. The
 	// interpretation of audience values is generally application specific.
 	// Use of this claim is OPTIONAL.
-	Aud string `json:"aud,omitempty"`
+	Aud Audience `json:"aud,omitempty"`
 
 	J509
+
+	// Extra holds the header parameters that are not modeled by this struct, such as private parameters, or
+	// extensions listed in the "crit" header. Values are kept as raw JSON, so a header can be decoded and encoded
+	// again without losing them.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc7515#section-4.3
+	//
+	// A producer and consumer of a JWS may agree to use Header Parameter
+	// names that are Private Names: names that are not Registered Header
+	// Parameter names (Section 4.1) or Public Header Parameter names
+	// (Section 4.2). Unlike Public Header Parameter names, Private Header
+	// Parameter names are subject to collision and should be used with
+	// caution.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// jwhParameters lists the header parameters modeled by JWH.
+var jwhParameters = []string{
+	"typ", "cty", "alg", "enc", "zip", "jku", "jwk", "kid", "crit", "b64", "iss", "sub", "aud",
+	"x5u", "x5c", "x5t", "x5t#S256",
+}
+
+func (header JWH) MarshalJSON() ([]byte, error) {
+	// The alias drops the methods of JWH, to prevent an infinite recursion.
+	type jwh JWH
+
+	return members.Marshal(jwh(header), header.Extra, jwhParameters)
+}
+
+// UnmarshalJSON decodes the header. Parameter names are case-sensitive: a name that only matches a modeled
+// parameter by its case, such as "ALG", is rejected.
+func (header *JWH) UnmarshalJSON(data []byte) error {
+	type jwh JWH
+
+	var decoded jwh
+
+	extra, err := members.Unmarshal(data, &decoded, jwhParameters)
+	if err != nil {
+		return err
+	}
+
+	*header = JWH(decoded)
+	header.Extra = extra
+
+	return nil
 }
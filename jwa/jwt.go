@@ -57,7 +57,7 @@ type Claims struct {
 	// single case-sensitive string containing a StringOrURI value. The
 	// interpretation of audience values is generally application specific.
 	// Use of this claim is OPTIONAL.
-	Aud string `json:"aud,omitempty"`
+	Aud Audience `json:"aud,omitempty"`
 
 	// Exp is the expiration time of the token.
 	//
@@ -69,7 +69,7 @@ type Claims struct {
 	// Implementers MAY provide for some small leeway, usually no more than
 	// a few minutes, to account for clock skew. Its value MUST be a number
 	// containing a NumericDate value. Use of this claim is OPTIONAL.
-	Exp int64 `json:"exp,omitempty"`
+	Exp NumericDate `json:"exp,omitempty"`
 	// Nbf is the "not before" time of the token.
 	//
 	// https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.5
@@ -81,7 +81,7 @@ type Claims struct {
 	// provide for some small leeway, usually no more than a few minutes, to
 	// account for clock skew. Its value MUST be a number containing a
 	// NumericDate value. Use of this claim is OPTIONAL.
-	Nbf int64 `json:"nbf,omitempty"`
+	Nbf NumericDate `json:"nbf,omitempty"`
 	// Iat is the time at which the token was issued.
 	//
 	// https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.6
@@ -90,7 +90,7 @@ type Claims struct {
 	// issued. This claim can be used to determine the age of the JWT. Its
 	// value MUST be a number containing a NumericDate value. Use of this
 	// claim is OPTIONAL.
-	Iat int64 `json:"iat,omitempty"`
+	Iat NumericDate `json:"iat,omitempty"`
 
 	// Jti is the JWT ID of the token.
 	//
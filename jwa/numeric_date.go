@@ -0,0 +1,68 @@
+package jwa
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Bounds of the seconds that can be converted to a time.Time without overflowing its internal representation,
+// which counts seconds from year 1 instead of 1970.
+const (
+	minNumericDateSeconds = math.MinInt64
+	maxNumericDateSeconds = math.MaxInt64 - 62135596800
+)
+
+// NumericDate is a time value used by the "exp", "nbf" and "iat" claims.
+//
+// https://datatracker.ietf.org/doc/html/rfc7519#section-2
+//
+// A JSON numeric value representing the number of seconds from
+// 1970-01-01T00:00:00Z UTC until the specified UTC date/time,
+// ignoring leap seconds. This is equivalent to the IEEE Std 1003.1,
+// 2013 Edition [POSIX.1] definition "Seconds Since the Epoch", in
+// which each day is accounted for by exactly 86400 seconds, other
+// than that non-integer values can be represented.
+type NumericDate float64
+
+// NewNumericDate converts a time to a NumericDate. The time is truncated to the second, since many
+// implementations only support integer values.
+func NewNumericDate(t time.Time) NumericDate {
+	return NumericDate(t.Unix())
+}
+
+// Time converts the NumericDate to a time, keeping fractional seconds. Values beyond the range of time.Time,
+// including infinities, are clamped to the earliest or latest representable time. NaN converts to the zero time.
+func (date NumericDate) Time() time.Time {
+	value := float64(date)
+
+	switch {
+	case math.IsNaN(value):
+		return time.Time{}
+	case value <= minNumericDateSeconds:
+		return time.Unix(minNumericDateSeconds, 0)
+	case value >= maxNumericDateSeconds:
+		return time.Unix(maxNumericDateSeconds, 0)
+	}
+
+	seconds, fraction := math.Modf(value)
+
+	return time.Unix(int64(seconds), int64(math.Round(fraction*float64(time.Second))))
+}
+
+// UnmarshalJSON decodes a NumericDate, rejecting values outside the range of int64 seconds.
+func (date *NumericDate) UnmarshalJSON(data []byte) error {
+	var value float64
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	if math.IsNaN(value) || math.IsInf(value, 0) || value < math.MinInt64 || value >= math.MaxInt64 {
+		return fmt.Errorf("unmarshal numeric date: %s is out of range", data)
+	}
+
+	*date = NumericDate(value)
+
+	return nil
+}
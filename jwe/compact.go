@@ -0,0 +1,221 @@
+package jwecore
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+	"github.com/a-novel-kit/jwt-core/jwe/enc"
+	jwkcore "github.com/a-novel-kit/jwt-core/jwk"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+)
+
+var (
+	ErrUnsupportedAlg = errors.New("unsupported key management algorithm")
+	ErrUnsupportedEnc = errors.New("unsupported content encryption algorithm")
+	ErrUnsupportedZip = errors.New("unsupported compression algorithm")
+	ErrInvalidKey     = errors.New("invalid key for algorithm")
+	ErrMalformedToken = errors.New("malformed token")
+)
+
+// JWE is a JSON Web Encryption, in its compact serialization.
+//
+// https://datatracker.ietf.org/doc/html/rfc7516#section-7.1
+type JWE struct {
+	// Header is the decoded JWE Protected Header.
+	Header Header
+	// AAD is the Additional Authenticated Data of the content encryption. In the compact serialization, it is the
+	// encoded protected header, as it appears in the token.
+	AAD string
+
+	// EncryptedKey is the JWE Encrypted Key. It is empty for direct key agreement or direct encryption.
+	EncryptedKey []byte
+	// IV is the JWE Initialization Vector.
+	IV []byte
+	// Ciphertext is the JWE Ciphertext.
+	Ciphertext []byte
+	// Tag is the JWE Authentication Tag.
+	Tag []byte
+}
+
+// Encrypt creates a new JWE in compact serialization. The key management mode is read from the "alg" header,
+// and the content encryption algorithm from the "enc" header. Header parameters required by the key management
+// algorithm ("epk", "iv", "tag", "p2s", "p2c", etc.) are set automatically. The optional "apu" and "apv"
+// parameters of ECDH-ES can be set in the Extra member of the header.
+//
+// The key must match the type expected by the key management algorithm:
+//
+//   - RSA1_5, RSA-OAEP, RSA-OAEP-256: *rsa.PublicKey
+//   - A128KW, A192KW, A256KW: []byte (key encryption key)
+//   - A128GCMKW, A192GCMKW, A256GCMKW: []byte (key encryption key)
+//   - dir: []byte (content encryption key)
+//   - ECDH-ES, ECDH-ES+A128KW, ECDH-ES+A192KW, ECDH-ES+A256KW: *ecdsa.PublicKey, or *ecdh.PublicKey for X25519
+//   - PBES2-HS256+A128KW, PBES2-HS384+A192KW, PBES2-HS512+A256KW: []byte (password)
+func Encrypt(header jwa.JWH, plaintext []byte, key any) (string, error) {
+	compressed, err := compress(header.Zip, plaintext)
+	if err != nil {
+		return "", fmt.Errorf("compress plaintext: %w", err)
+	}
+
+	// Key management parameters passed as extra members, such as "apu" and "apv", are decoded into their
+	// dedicated fields, so the key management algorithm can use them.
+	fullHeader := new(Header)
+	if err = jwtcore.MergeHeaders(fullHeader, header); err != nil {
+		return "", fmt.Errorf("read header: %w", err)
+	}
+
+	cek, encryptedKey, err := encryptKey(fullHeader, key)
+	if err != nil {
+		return "", fmt.Errorf("encrypt key: %w", err)
+	}
+
+	preset, err := contentPreset(header.Enc)
+	if err != nil {
+		return "", err
+	}
+
+	iv, err := jwkgen.IV(preset.IVSize)
+	if err != nil {
+		return "", fmt.Errorf("generate iv: %w", err)
+	}
+
+	// The protected header must be computed after the key management, as it may set additional parameters.
+	encodedHeader, err := jwtcore.Encode(fullHeader)
+	if err != nil {
+		return "", fmt.Errorf("encode header: %w", err)
+	}
+
+	// Let the Additional Authenticated Data encryption parameter be ASCII(Encoded Protected Header).
+	//
+	// https://datatracker.ietf.org/doc/html/rfc7516#section-5.1
+	encrypted, err := encryptContent(header.Enc, compressed, []byte(encodedHeader), &jwkcore.AESKeySet{CEK: cek, IV: iv})
+	if err != nil {
+		return "", fmt.Errorf("encrypt content: %w", err)
+	}
+
+	return jwtcore.Assemble(
+		encodedHeader,
+		base64.RawURLEncoding.EncodeToString(encryptedKey),
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(encrypted.E),
+		base64.RawURLEncoding.EncodeToString(encrypted.T),
+	), nil
+}
+
+// Parse reads a JWE in compact serialization. It does not decrypt the content: use JWE.Decrypt for this purpose.
+func Parse(token string) (*JWE, error) {
+	return ParseWithConfig(token, nil)
+}
+
+// ParseWithConfig works like Parse, with the size limits of a custom configuration. A nil configuration uses the
+// defaults.
+func ParseWithConfig(token string, config *DecryptConfig) (*JWE, error) {
+	limits := config.limits()
+
+	if err := limits.CheckTokenSize(len(token)); err != nil {
+		return nil, err
+	}
+
+	parts, err := jwtcore.DisassembleN(token, 5)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 segments, got %d", ErrMalformedToken, len(parts))
+	}
+
+	if err = limits.CheckHeaderSize(len(parts[0])); err != nil {
+		return nil, err
+	}
+
+	if err = limits.CheckPayloadSize(len(parts[3])); err != nil {
+		return nil, err
+	}
+
+	var header Header
+	if err = jwtcore.DecodeStrict(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: decode header: %w", ErrMalformedToken, err)
+	}
+
+	if header.Alg == "" || header.Enc == "" {
+		return nil, fmt.Errorf("%w: missing alg or enc header", ErrMalformedToken)
+	}
+
+	segments := make([][]byte, 4)
+	for i, name := range []string{"encrypted key", "iv", "ciphertext", "tag"} {
+		segment, err := jwtcore.DecodeSegment(parts[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: decode %s: %w", ErrMalformedToken, name, err)
+		}
+
+		segments[i] = segment
+	}
+
+	return &JWE{
+		Header:       header,
+		AAD:          parts[0],
+		EncryptedKey: segments[0],
+		IV:           segments[1],
+		Ciphertext:   segments[2],
+		Tag:          segments[3],
+	}, nil
+}
+
+// Decrypt retrieves the content encryption key using the key management algorithm of the token, and returns
+// the decrypted plaintext. The key must be the private counterpart of the one used for encryption (or the same
+// secret, for symmetric algorithms). RSA-OAEP and RSA-OAEP-256 also accept any crypto.Decrypter backed by an RSA
+// key.
+func (token *JWE) Decrypt(key any) ([]byte, error) {
+	return token.DecryptWithConfig(key, nil)
+}
+
+// DecryptWithConfig works like Decrypt, with a custom configuration. A nil configuration uses the defaults.
+func (token *JWE) DecryptWithConfig(key any, config *DecryptConfig) ([]byte, error) {
+	if err := jwtcore.CheckCritical(token.AAD, config.critical()); err != nil {
+		return nil, fmt.Errorf("check critical header: %w", err)
+	}
+
+	if token.Header.Zip != "" && token.Header.Zip != jwa.ZipDeflate {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedZip, token.Header.Zip)
+	}
+
+	cek, err := decryptKey(&token.Header, token.EncryptedKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt key: %w", err)
+	}
+
+	plaintext, err := decryptContent(
+		token.Header.Enc,
+		&enc.AESPayload{E: token.Ciphertext, T: token.Tag},
+		[]byte(token.AAD),
+		&jwkcore.AESKeySet{CEK: cek, IV: token.IV},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt content: %w", err)
+	}
+
+	plaintext, err = decompress(token.Header.Zip, plaintext, config)
+	if err != nil {
+		return nil, fmt.Errorf("decompress plaintext: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// Decrypt parses a JWE in compact serialization, and returns its decrypted plaintext.
+func Decrypt(token string, key any) ([]byte, error) {
+	return DecryptWithConfig(token, key, nil)
+}
+
+// DecryptWithConfig works like Decrypt, with a custom configuration. A nil configuration uses the defaults.
+func DecryptWithConfig(token string, key any, config *DecryptConfig) ([]byte, error) {
+	parsed, err := ParseWithConfig(token, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed.DecryptWithConfig(key, config)
+}
@@ -0,0 +1,621 @@
+package jwecore_test
+
+import (
+	"crypto"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwecore "github.com/a-novel-kit/jwt-core/jwe"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+)
+
+func TestEncryptAndDecrypt(t *testing.T) {
+	rsaKey, err := jwkgen.RSA(jwkgen.RS256KeySize)
+	require.NoError(t, err)
+
+	ecKey, err := jwkgen.EC(elliptic.P256())
+	require.NoError(t, err)
+
+	ecKey521, err := jwkgen.EC(elliptic.P521())
+	require.NoError(t, err)
+
+	x25519Key, err := jwkgen.X25519()
+	require.NoError(t, err)
+
+	kek128, err := jwkgen.AES(jwkgen.AESKeySize128)
+	require.NoError(t, err)
+
+	kek256, err := jwkgen.AES(jwkgen.AESKeySize256)
+	require.NoError(t, err)
+
+	cek512, err := jwkgen.AES(jwkgen.AESKeySize512)
+	require.NoError(t, err)
+
+	password := []byte("Thus from my lips, by yours, my sin is purged.")
+
+	testCases := []struct {
+		name string
+
+		alg jwa.Alg
+		enc jwa.Enc
+
+		encryptKey any
+		decryptKey any
+
+		expectHeader func(t *testing.T, header *jwecore.Header)
+	}{
+		{
+			name:       "RSA1_5/A128CBC",
+			alg:        jwa.RSA15,
+			enc:        jwa.A128CBC,
+			encryptKey: &rsaKey.PublicKey,
+			decryptKey: rsaKey,
+		},
+		{
+			name:       "RSA-OAEP/A256GCM",
+			alg:        jwa.RSAOAEP,
+			enc:        jwa.A256GCM,
+			encryptKey: &rsaKey.PublicKey,
+			decryptKey: rsaKey,
+		},
+		{
+			name:       "RSA-OAEP-256/A192CBC",
+			alg:        jwa.RSAOAEP256,
+			enc:        jwa.A192CBC,
+			encryptKey: &rsaKey.PublicKey,
+			decryptKey: rsaKey,
+		},
+		{
+			name:       "A128KW/A128GCM",
+			alg:        jwa.A128KW,
+			enc:        jwa.A128GCM,
+			encryptKey: kek128,
+			decryptKey: kek128,
+		},
+		{
+			name:       "A256KW/A256CBC",
+			alg:        jwa.A256KW,
+			enc:        jwa.A256CBC,
+			encryptKey: kek256,
+			decryptKey: kek256,
+		},
+		{
+			name:       "A128GCMKW/A192GCM",
+			alg:        jwa.A128GCMKW,
+			enc:        jwa.A192GCM,
+			encryptKey: kek128,
+			decryptKey: kek128,
+			expectHeader: func(t *testing.T, header *jwecore.Header) {
+				t.Helper()
+
+				require.NotEmpty(t, header.IV)
+				require.NotEmpty(t, header.Tag)
+			},
+		},
+		{
+			name:       "A256GCMKW/A128CBC",
+			alg:        jwa.A256GCMKW,
+			enc:        jwa.A128CBC,
+			encryptKey: kek256,
+			decryptKey: kek256,
+		},
+		{
+			name:       "dir/A256CBC",
+			alg:        jwa.DIR,
+			enc:        jwa.A256CBC,
+			encryptKey: cek512,
+			decryptKey: cek512,
+		},
+		{
+			name:       "ECDH-ES/A128GCM",
+			alg:        jwa.ECDHES,
+			enc:        jwa.A128GCM,
+			encryptKey: &ecKey.PublicKey,
+			decryptKey: ecKey,
+			expectHeader: func(t *testing.T, header *jwecore.Header) {
+				t.Helper()
+
+				require.NotNil(t, header.EPK)
+				require.Equal(t, jwa.KTYEC, header.EPK.KTY)
+				require.Equal(t, "P-256", header.EPK.Crv)
+			},
+		},
+		{
+			name:       "ECDH-ES+A256KW/A256GCM",
+			alg:        jwa.ECDHESA256KW,
+			enc:        jwa.A256GCM,
+			encryptKey: &ecKey521.PublicKey,
+			decryptKey: ecKey521,
+			expectHeader: func(t *testing.T, header *jwecore.Header) {
+				t.Helper()
+
+				require.NotNil(t, header.EPK)
+				require.Equal(t, "P-521", header.EPK.Crv)
+			},
+		},
+		{
+			name:       "ECDH-ES+A128KW/A128CBC/X25519",
+			alg:        jwa.ECDHESA128KW,
+			enc:        jwa.A128CBC,
+			encryptKey: x25519Key.PublicKey(),
+			decryptKey: x25519Key,
+			expectHeader: func(t *testing.T, header *jwecore.Header) {
+				t.Helper()
+
+				require.NotNil(t, header.EPK)
+				require.Equal(t, jwa.KTYOKP, header.EPK.KTY)
+				require.Equal(t, "X25519", header.EPK.Crv)
+			},
+		},
+		{
+			name:       "PBES2-HS256+A128KW/A128GCM",
+			alg:        jwa.PBES2HS256A128KW,
+			enc:        jwa.A128GCM,
+			encryptKey: password,
+			decryptKey: password,
+			expectHeader: func(t *testing.T, header *jwecore.Header) {
+				t.Helper()
+
+				require.NotEmpty(t, header.P2S)
+				require.Equal(t, jwecore.PBES2DefaultCount, header.P2C)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			plaintext := []byte("Live long and prosper.")
+
+			token, err := jwecore.Encrypt(
+				jwa.JWH{Alg: testCase.alg, Enc: testCase.enc, KID: "key-1"}, plaintext, testCase.encryptKey,
+			)
+			require.NoError(t, err)
+			require.Len(t, jwtcore.Disassemble(token), 5)
+
+			parsed, err := jwecore.Parse(token)
+			require.NoError(t, err)
+			require.Equal(t, testCase.alg, parsed.Header.Alg)
+			require.Equal(t, testCase.enc, parsed.Header.Enc)
+			require.Equal(t, "key-1", parsed.Header.KID)
+
+			if testCase.expectHeader != nil {
+				testCase.expectHeader(t, &parsed.Header)
+			}
+
+			decrypted, err := parsed.Decrypt(testCase.decryptKey)
+			require.NoError(t, err)
+			require.Equal(t, plaintext, decrypted)
+
+			t.Run("tampered header", func(t *testing.T) {
+				parts := jwtcore.Disassemble(token)
+
+				header := parsed.Header
+				header.KID = "key-2"
+
+				tamperedHeader, err := jwtcore.Encode(header)
+				require.NoError(t, err)
+
+				parts[0] = tamperedHeader
+
+				_, err = jwecore.Decrypt(jwtcore.Assemble(parts...), testCase.decryptKey)
+				require.Error(t, err)
+			})
+		})
+	}
+}
+
+// https://datatracker.ietf.org/doc/html/rfc7516#appendix-A.3
+func TestDecryptVector(t *testing.T) {
+	key, err := base64.RawURLEncoding.DecodeString("GawgguFyGrWKav7AX4VKUg")
+	require.NoError(t, err)
+
+	token := "eyJhbGciOiJBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0." +
+		"6KB707dM9YTIgHtLvtgWQ8mKwboJW3of9locizkDTHzBC2IlrT1oOQ." +
+		"AxY8DCtDaGlsbGljb3RoZQ." +
+		"KDlTtXchhZTGufMYmOYGS4HffxPSUrfmqCHXaI9wOGY." +
+		"U0m_YmjN04DJvceFICbCVQ"
+
+	decrypted, err := jwecore.Decrypt(token, key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("Live long and prosper."), decrypted)
+}
+
+func TestEncrypt(t *testing.T) {
+	rsaKey, err := jwkgen.RSA(jwkgen.RS256KeySize)
+	require.NoError(t, err)
+
+	kek128, err := jwkgen.AES(jwkgen.AESKeySize128)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		header jwa.JWH
+		key    any
+
+		expect error
+	}{
+		{
+			name:   "unsupported alg",
+			header: jwa.JWH{Alg: "foo", Enc: jwa.A128GCM},
+			key:    kek128,
+			expect: jwecore.ErrUnsupportedAlg,
+		},
+		{
+			name:   "unsupported enc",
+			header: jwa.JWH{Alg: jwa.A128KW, Enc: "foo"},
+			key:    kek128,
+			expect: jwecore.ErrUnsupportedEnc,
+		},
+		{
+			name:   "missing enc",
+			header: jwa.JWH{Alg: jwa.A128KW},
+			key:    kek128,
+			expect: jwecore.ErrUnsupportedEnc,
+		},
+		{
+			name:   "wrong key type",
+			header: jwa.JWH{Alg: jwa.RSAOAEP, Enc: jwa.A128GCM},
+			key:    rsaKey,
+			expect: jwecore.ErrInvalidKey,
+		},
+		{
+			name:   "wrong kek size",
+			header: jwa.JWH{Alg: jwa.A256KW, Enc: jwa.A128GCM},
+			key:    kek128,
+			expect: jwecore.ErrInvalidKey,
+		},
+		{
+			name:   "wrong gcm kek size",
+			header: jwa.JWH{Alg: jwa.A192GCMKW, Enc: jwa.A128GCM},
+			key:    kek128,
+			expect: jwecore.ErrInvalidKey,
+		},
+		{
+			name:   "wrong cek size",
+			header: jwa.JWH{Alg: jwa.DIR, Enc: jwa.A128CBC},
+			key:    kek128,
+			expect: jwecore.ErrInvalidKey,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := jwecore.Encrypt(testCase.header, []byte("foo"), testCase.key)
+			require.ErrorIs(t, err, testCase.expect)
+		})
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	kek, err := jwkgen.AES(jwkgen.AESKeySize128)
+	require.NoError(t, err)
+
+	otherKEK, err := jwkgen.AES(jwkgen.AESKeySize128)
+	require.NoError(t, err)
+
+	token, err := jwecore.Encrypt(jwa.JWH{Alg: jwa.A128KW, Enc: jwa.A128GCM}, []byte("foo"), kek)
+	require.NoError(t, err)
+
+	parts := jwtcore.Disassemble(token)
+
+	testCases := []struct {
+		name string
+
+		token string
+		key   any
+
+		expect      error
+		expectError bool
+	}{
+		{
+			name:  "ok",
+			token: token,
+			key:   kek,
+		},
+		{
+			name:   "four segments",
+			token:  jwtcore.Assemble(parts[:4]...),
+			key:    kek,
+			expect: jwecore.ErrMalformedToken,
+		},
+		{
+			name:   "invalid iv",
+			token:  jwtcore.Assemble(parts[0], parts[1], "&/?", parts[3], parts[4]),
+			key:    kek,
+			expect: jwecore.ErrMalformedToken,
+		},
+		{
+			name:   "truncated iv",
+			token:  jwtcore.Assemble(parts[0], parts[1], parts[2][:4], parts[3], parts[4]),
+			key:    kek,
+			expect: jwecore.ErrMalformedToken,
+		},
+		{
+			name:        "wrong key",
+			token:       token,
+			key:         otherKEK,
+			expectError: true,
+		},
+		{
+			name:        "tampered ciphertext",
+			token:       jwtcore.Assemble(parts[0], parts[1], parts[2], "Zm9v", parts[4]),
+			key:         kek,
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			decrypted, err := jwecore.Decrypt(testCase.token, testCase.key)
+
+			switch {
+			case testCase.expect != nil:
+				require.ErrorIs(t, err, testCase.expect)
+			case testCase.expectError:
+				require.Error(t, err)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, []byte("foo"), decrypted)
+			}
+		})
+	}
+}
+
+func TestDecryptPBES2Parameters(t *testing.T) {
+	password := []byte("password")
+
+	token, err := jwecore.Encrypt(jwa.JWH{Alg: jwa.PBES2HS256A128KW, Enc: jwa.A128GCM}, []byte("foo"), password)
+	require.NoError(t, err)
+
+	parsed, err := jwecore.Parse(token)
+	require.NoError(t, err)
+
+	parts := jwtcore.Disassemble(token)
+
+	testCases := []struct {
+		name string
+
+		p2s string
+		p2c int
+	}{
+		{
+			name: "short salt",
+			p2s:  base64.RawURLEncoding.EncodeToString([]byte("salt")),
+			p2c:  parsed.Header.P2C,
+		},
+		{
+			name: "missing salt",
+			p2c:  parsed.Header.P2C,
+		},
+		{
+			name: "zero count",
+			p2s:  parsed.Header.P2S,
+		},
+		{
+			name: "count too large",
+			p2s:  parsed.Header.P2S,
+			p2c:  jwecore.PBES2MaxCount + 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			header := parsed.Header
+			header.P2S = testCase.p2s
+			header.P2C = testCase.p2c
+
+			encodedHeader, err := jwtcore.Encode(header)
+			require.NoError(t, err)
+
+			_, err = jwecore.Decrypt(jwtcore.Assemble(encodedHeader, parts[1], parts[2], parts[3], parts[4]), password)
+			require.ErrorIs(t, err, jwecore.ErrMalformedToken)
+		})
+	}
+}
+
+// opaqueDecrypter hides the concrete type of a private key, like a key stored in an HSM or a KMS would.
+type opaqueDecrypter struct {
+	key crypto.Decrypter
+}
+
+func (d *opaqueDecrypter) Public() crypto.PublicKey {
+	return d.key.Public()
+}
+
+func (d *opaqueDecrypter) Decrypt(rand io.Reader, msg []byte, opts crypto.DecrypterOpts) ([]byte, error) {
+	return d.key.Decrypt(rand, msg, opts)
+}
+
+func TestDecryptWithDecrypter(t *testing.T) {
+	rsaKey, err := jwkgen.RSA(jwkgen.RS256KeySize)
+	require.NoError(t, err)
+
+	ecKey, err := jwkgen.EC(elliptic.P256())
+	require.NoError(t, err)
+
+	for _, alg := range []jwa.Alg{jwa.RSAOAEP, jwa.RSAOAEP256} {
+		t.Run(string(alg), func(t *testing.T) {
+			token, err := jwecore.Encrypt(jwa.JWH{Alg: alg, Enc: jwa.A128GCM}, []byte("foo"), &rsaKey.PublicKey)
+			require.NoError(t, err)
+
+			decrypted, err := jwecore.Decrypt(token, &opaqueDecrypter{key: rsaKey})
+			require.NoError(t, err)
+			require.Equal(t, []byte("foo"), decrypted)
+		})
+	}
+
+	t.Run("wrong key type", func(t *testing.T) {
+		token, err := jwecore.Encrypt(jwa.JWH{Alg: jwa.RSAOAEP, Enc: jwa.A128GCM}, []byte("foo"), &rsaKey.PublicKey)
+		require.NoError(t, err)
+
+		_, err = jwecore.Decrypt(token, ecKey)
+		require.ErrorIs(t, err, jwecore.ErrInvalidKey)
+	})
+}
+
+func TestEncryptAgreementInfo(t *testing.T) {
+	ecKey, err := jwkgen.EC(elliptic.P256())
+	require.NoError(t, err)
+
+	for _, alg := range []jwa.Alg{jwa.ECDHES, jwa.ECDHESA128KW} {
+		t.Run(string(alg), func(t *testing.T) {
+			header := jwa.JWH{
+				Alg: alg,
+				Enc: jwa.A128GCM,
+				Extra: map[string]json.RawMessage{
+					"apu": json.RawMessage(`"QWxpY2U"`),
+					"apv": json.RawMessage(`"Qm9i"`),
+				},
+			}
+
+			token, err := jwecore.Encrypt(header, []byte("foo"), &ecKey.PublicKey)
+			require.NoError(t, err)
+
+			parsed, err := jwecore.Parse(token)
+			require.NoError(t, err)
+			require.Equal(t, "QWxpY2U", parsed.Header.APU)
+			require.Equal(t, "Qm9i", parsed.Header.APV)
+
+			decrypted, err := parsed.Decrypt(ecKey)
+			require.NoError(t, err)
+			require.Equal(t, []byte("foo"), decrypted)
+		})
+	}
+}
+
+func TestDecryptCritical(t *testing.T) {
+	kek, err := jwkgen.AES(jwkgen.AESKeySize128)
+	require.NoError(t, err)
+
+	b64 := true
+
+	testCases := []struct {
+		name string
+
+		header jwa.JWH
+		config *jwecore.DecryptConfig
+
+		expect error
+	}{
+		{
+			name:   "supported",
+			header: jwa.JWH{Alg: jwa.A128KW, Enc: jwa.A128GCM, Crit: []string{"b64"}, B64: &b64},
+			config: &jwecore.DecryptConfig{Critical: []string{"b64"}},
+		},
+		{
+			name:   "unsupported",
+			header: jwa.JWH{Alg: jwa.A128KW, Enc: jwa.A128GCM, Crit: []string{"b64"}, B64: &b64},
+			expect: jwtcore.ErrUnsupportedCritical,
+		},
+		{
+			name:   "registered parameter",
+			header: jwa.JWH{Alg: jwa.A128KW, Enc: jwa.A128GCM, Crit: []string{"enc"}},
+			config: &jwecore.DecryptConfig{Critical: []string{"enc"}},
+			expect: jwtcore.ErrInvalidCritical,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			token, err := jwecore.Encrypt(testCase.header, []byte("foo"), kek)
+			require.NoError(t, err)
+
+			_, err = jwecore.DecryptWithConfig(token, kek, testCase.config)
+			require.ErrorIs(t, err, testCase.expect)
+
+			doc, err := jwecore.EncryptJSON(
+				jwecore.JSONHeaders{Protected: &testCase.header}, []byte("foo"), nil,
+				jwecore.JSONRecipientKey{Key: kek},
+			)
+			require.NoError(t, err)
+
+			_, err = doc.DecryptWithConfig(kek, testCase.config)
+			require.ErrorIs(t, err, testCase.expect)
+		})
+	}
+
+	t.Run("unprotected crit", func(t *testing.T) {
+		doc, err := jwecore.EncryptJSON(
+			jwecore.JSONHeaders{
+				Protected:   &jwa.JWH{Enc: jwa.A128GCM, B64: &b64},
+				Unprotected: &jwa.JWH{Crit: []string{"b64"}},
+			},
+			[]byte("foo"), nil,
+			jwecore.JSONRecipientKey{Header: &jwa.JWH{Alg: jwa.A128KW}, Key: kek},
+		)
+		require.NoError(t, err)
+
+		_, err = doc.DecryptWithConfig(kek, &jwecore.DecryptConfig{Critical: []string{"b64"}})
+		require.ErrorIs(t, err, jwtcore.ErrInvalidCritical)
+	})
+}
+
+func TestDecryptLimits(t *testing.T) {
+	kek, err := jwkgen.AES(jwkgen.AESKeySize128)
+	require.NoError(t, err)
+
+	token, err := jwecore.Encrypt(jwa.JWH{Alg: jwa.A128KW, Enc: jwa.A128GCM}, make([]byte, 300), kek)
+	require.NoError(t, err)
+
+	doc, err := jwecore.EncryptJSON(
+		jwecore.JSONHeaders{Protected: &jwa.JWH{Alg: jwa.A128KW, Enc: jwa.A128GCM}}, make([]byte, 300), nil,
+		jwecore.JSONRecipientKey{Key: kek},
+	)
+	require.NoError(t, err)
+
+	serialized, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		limits *jwtcore.Limits
+
+		expect error
+	}{
+		{
+			name: "default limits",
+		},
+		{
+			name:   "token too large",
+			limits: &jwtcore.Limits{MaxTokenSize: 300},
+			expect: jwtcore.ErrLimitExceeded,
+		},
+		{
+			name:   "header too large",
+			limits: &jwtcore.Limits{MaxHeaderSize: 10},
+			expect: jwtcore.ErrLimitExceeded,
+		},
+		{
+			name:   "ciphertext too large",
+			limits: &jwtcore.Limits{MaxPayloadSize: 300},
+			expect: jwtcore.ErrLimitExceeded,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			config := &jwecore.DecryptConfig{Limits: testCase.limits}
+
+			_, err := jwecore.DecryptWithConfig(token, kek, config)
+			require.ErrorIs(t, err, testCase.expect)
+
+			_, err = jwecore.ParseJSONWithConfig(serialized, config)
+			require.ErrorIs(t, err, testCase.expect)
+		})
+	}
+
+	t.Run("too many segments", func(t *testing.T) {
+		_, err := jwecore.Parse(token + strings.Repeat(".", 1000))
+		require.ErrorIs(t, err, jwtcore.ErrTooManySegments)
+	})
+}
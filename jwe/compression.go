@@ -0,0 +1,141 @@
+package jwecore
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+)
+
+const (
+	// DefaultMaxInflatedSize is the default maximum size, in bytes, of a decompressed plaintext.
+	DefaultMaxInflatedSize = 1 << 20
+	// DefaultMaxInflateRatio is the default maximum ratio between the size of a decompressed plaintext, and the
+	// size of its compressed representation.
+	DefaultMaxInflateRatio = 100
+)
+
+var ErrInflateLimit = errors.New("decompressed plaintext exceeds the limit")
+
+// DecryptConfig customizes the decryption of a JWE.
+type DecryptConfig struct {
+	// MaxInflatedSize is the maximum size, in bytes, of a decompressed plaintext. If zero,
+	// DefaultMaxInflatedSize is used.
+	MaxInflatedSize int
+	// MaxInflateRatio is the maximum ratio between the size of a decompressed plaintext, and the size of its
+	// compressed representation. If zero, DefaultMaxInflateRatio is used.
+	//
+	// Along with MaxInflatedSize, it prevents a small token from expanding into a huge plaintext when
+	// decompressed.
+	MaxInflateRatio int
+	// Critical lists the extension header parameters understood by the caller, which may appear in the "crit"
+	// header. A token listing any other parameter in its "crit" header is rejected with
+	// jwtcore.ErrUnsupportedCritical.
+	Critical []string
+	// Limits bounds the size of the token when it is parsed. If nil, the default limits are used.
+	Limits *jwtcore.Limits
+}
+
+// critical returns the extension header parameters supported with this configuration.
+func (config *DecryptConfig) critical() []string {
+	if config == nil {
+		return nil
+	}
+
+	return config.Critical
+}
+
+// limits returns the size limits of this configuration.
+func (config *DecryptConfig) limits() *jwtcore.Limits {
+	if config == nil {
+		return nil
+	}
+
+	return config.Limits
+}
+
+// compress applies the compression algorithm of the "zip" header to the plaintext, before encryption.
+//
+// https://datatracker.ietf.org/doc/html/rfc7516#section-5.1
+//
+// If a "zip" parameter was included, compress the plaintext using the specified compression algorithm and let M
+// be the octet sequence representing the compressed plaintext; otherwise, let M be the octet sequence
+// representing the plaintext.
+func compress(zip jwa.Zip, plaintext []byte) ([]byte, error) {
+	switch zip {
+	case "":
+		return plaintext, nil
+	case jwa.ZipDeflate:
+		var buf bytes.Buffer
+
+		// Compression with the DEFLATE [RFC1951] algorithm. No zlib or gzip framing is used.
+		writer, err := flate.NewWriter(&buf, flate.BestCompression)
+		if err != nil {
+			return nil, fmt.Errorf("new deflate writer: %w", err)
+		}
+
+		if _, err = writer.Write(plaintext); err != nil {
+			return nil, fmt.Errorf("deflate: %w", err)
+		}
+
+		if err = writer.Close(); err != nil {
+			return nil, fmt.Errorf("deflate: %w", err)
+		}
+
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedZip, zip)
+	}
+}
+
+// decompress reverts the compression algorithm of the "zip" header, after decryption.
+//
+// https://datatracker.ietf.org/doc/html/rfc7516#section-5.2
+//
+// If a "zip" parameter was included, uncompress the decrypted plaintext using the specified compression
+// algorithm.
+func decompress(zip jwa.Zip, data []byte, config *DecryptConfig) ([]byte, error) {
+	switch zip {
+	case "":
+		return data, nil
+	case jwa.ZipDeflate:
+		limit := min(config.maxInflatedSize(), len(data)*config.maxInflateRatio())
+
+		reader := flate.NewReader(bytes.NewReader(data))
+		defer reader.Close()
+
+		// Read one more byte than allowed, to detect payloads that exceed the limit.
+		plaintext, err := io.ReadAll(io.LimitReader(reader, int64(limit)+1))
+		if err != nil {
+			return nil, fmt.Errorf("inflate: %w", err)
+		}
+
+		if len(plaintext) > limit {
+			return nil, fmt.Errorf("%w: more than %d bytes", ErrInflateLimit, limit)
+		}
+
+		return plaintext, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedZip, zip)
+	}
+}
+
+func (config *DecryptConfig) maxInflatedSize() int {
+	if config == nil || config.MaxInflatedSize == 0 {
+		return DefaultMaxInflatedSize
+	}
+
+	return config.MaxInflatedSize
+}
+
+func (config *DecryptConfig) maxInflateRatio() int {
+	if config == nil || config.MaxInflateRatio == 0 {
+		return DefaultMaxInflateRatio
+	}
+
+	return config.MaxInflateRatio
+}
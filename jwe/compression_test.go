@@ -0,0 +1,122 @@
+package jwecore_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwecore "github.com/a-novel-kit/jwt-core/jwe"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+)
+
+func TestCompression(t *testing.T) {
+	kek, err := jwkgen.AES(jwkgen.AESKeySize128)
+	require.NoError(t, err)
+
+	plaintext := bytes.Repeat([]byte("Live long and prosper. "), 100)
+
+	t.Run("compact", func(t *testing.T) {
+		compressed, err := jwecore.Encrypt(
+			jwa.JWH{Alg: jwa.A128KW, Enc: jwa.A128GCM, Zip: jwa.ZipDeflate}, plaintext, kek,
+		)
+		require.NoError(t, err)
+
+		uncompressed, err := jwecore.Encrypt(jwa.JWH{Alg: jwa.A128KW, Enc: jwa.A128GCM}, plaintext, kek)
+		require.NoError(t, err)
+
+		require.Less(t, len(compressed), len(uncompressed))
+
+		decrypted, err := jwecore.Decrypt(compressed, kek)
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decrypted)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		doc, err := jwecore.EncryptJSON(
+			jwecore.JSONHeaders{Protected: &jwa.JWH{Enc: jwa.A128GCM, Zip: jwa.ZipDeflate}},
+			plaintext,
+			nil,
+			jwecore.JSONRecipientKey{Header: &jwa.JWH{Alg: jwa.A128KW}, Key: kek},
+		)
+		require.NoError(t, err)
+
+		decrypted, err := doc.Decrypt(kek)
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decrypted)
+	})
+
+	t.Run("JSON unprotected zip", func(t *testing.T) {
+		_, err := jwecore.EncryptJSON(
+			jwecore.JSONHeaders{
+				Protected:   &jwa.JWH{Enc: jwa.A128GCM},
+				Unprotected: &jwa.JWH{Zip: jwa.ZipDeflate},
+			},
+			plaintext,
+			nil,
+			jwecore.JSONRecipientKey{Header: &jwa.JWH{Alg: jwa.A128KW}, Key: kek},
+		)
+		require.ErrorIs(t, err, jwecore.ErrUnsupportedZip)
+	})
+
+	t.Run("unsupported zip", func(t *testing.T) {
+		_, err := jwecore.Encrypt(jwa.JWH{Alg: jwa.A128KW, Enc: jwa.A128GCM, Zip: "foo"}, plaintext, kek)
+		require.ErrorIs(t, err, jwecore.ErrUnsupportedZip)
+	})
+}
+
+func TestDecompressionLimits(t *testing.T) {
+	kek, err := jwkgen.AES(jwkgen.AESKeySize128)
+	require.NoError(t, err)
+
+	// 2MiB of zeros compress to a few kilobytes.
+	bomb := make([]byte, 2<<20)
+
+	token, err := jwecore.Encrypt(jwa.JWH{Alg: jwa.A128KW, Enc: jwa.A128GCM, Zip: jwa.ZipDeflate}, bomb, kek)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		config *jwecore.DecryptConfig
+
+		expect error
+	}{
+		{
+			name: "default",
+
+			expect: jwecore.ErrInflateLimit,
+		},
+		{
+			name: "size limit",
+
+			config: &jwecore.DecryptConfig{MaxInflatedSize: 1 << 20, MaxInflateRatio: 1 << 20},
+
+			expect: jwecore.ErrInflateLimit,
+		},
+		{
+			name: "ratio limit",
+
+			config: &jwecore.DecryptConfig{MaxInflatedSize: 4 << 20, MaxInflateRatio: 10},
+
+			expect: jwecore.ErrInflateLimit,
+		},
+		{
+			name: "within limits",
+
+			config: &jwecore.DecryptConfig{MaxInflatedSize: 4 << 20, MaxInflateRatio: 1 << 20},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			decrypted, err := jwecore.DecryptWithConfig(token, kek, testCase.config)
+			require.ErrorIs(t, err, testCase.expect)
+
+			if testCase.expect == nil {
+				require.Equal(t, bomb, decrypted)
+			}
+		})
+	}
+}
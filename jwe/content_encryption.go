@@ -0,0 +1,70 @@
+package jwecore
+
+import (
+	"crypto/aes"
+	"fmt"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+	"github.com/a-novel-kit/jwt-core/jwe/enc"
+	jwkcore "github.com/a-novel-kit/jwt-core/jwk"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+)
+
+// contentPresets maps each content encryption algorithm to the size of its CEK and IV.
+//
+// https://datatracker.ietf.org/doc/html/rfc7518#section-5.1
+var contentPresets = map[jwa.Enc]jwkgen.AESKeyPreset{
+	jwa.A128CBC: jwkgen.A128CBCKeyPreset,
+	jwa.A192CBC: jwkgen.A192CBCKeyPreset,
+	jwa.A256CBC: jwkgen.A256CBCKeyPreset,
+	jwa.A128GCM: jwkgen.A128GCMKeyPreset,
+	jwa.A192GCM: jwkgen.A192GCMKeyPreset,
+	jwa.A256GCM: jwkgen.A256GCMKeyPreset,
+}
+
+func contentPreset(encAlg jwa.Enc) (jwkgen.AESKeyPreset, error) {
+	preset, ok := contentPresets[encAlg]
+	if !ok {
+		return jwkgen.AESKeyPreset{}, fmt.Errorf("%w: %q", ErrUnsupportedEnc, encAlg)
+	}
+
+	return preset, nil
+}
+
+func encryptContent(encAlg jwa.Enc, plaintext, aad []byte, key *jwkcore.AESKeySet) (*enc.AESPayload, error) {
+	switch encAlg {
+	case jwa.A128CBC, jwa.A192CBC, jwa.A256CBC:
+		return enc.EncryptAESCBC(plaintext, aad, key)
+	case jwa.A128GCM, jwa.A192GCM, jwa.A256GCM:
+		return enc.EncryptAESGCM(plaintext, aad, key)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedEnc, encAlg)
+	}
+}
+
+func decryptContent(encAlg jwa.Enc, data *enc.AESPayload, aad []byte, key *jwkcore.AESKeySet) ([]byte, error) {
+	preset, err := contentPreset(encAlg)
+	if err != nil {
+		return nil, err
+	}
+
+	// The underlying ciphers panic on invalid sizes, so they must be checked beforehand.
+	if len(key.CEK) != int(preset.KeySize) {
+		return nil, fmt.Errorf("%w: invalid CEK size for %s", ErrInvalidKey, encAlg)
+	}
+
+	if len(key.IV) != int(preset.IVSize) {
+		return nil, fmt.Errorf("%w: invalid IV size for %s", ErrMalformedToken, encAlg)
+	}
+
+	switch encAlg {
+	case jwa.A128CBC, jwa.A192CBC, jwa.A256CBC:
+		if len(data.E) == 0 || len(data.E)%aes.BlockSize != 0 {
+			return nil, fmt.Errorf("%w: invalid ciphertext size for %s", ErrMalformedToken, encAlg)
+		}
+
+		return enc.DecryptAESCBC(data, aad, key)
+	default:
+		return enc.DecryptAESGCM(data, aad, key)
+	}
+}
@@ -0,0 +1,81 @@
+package jwecore
+
+import (
+	"encoding/json"
+
+	"github.com/a-novel-kit/jwt-core/internal/members"
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwejson "github.com/a-novel-kit/jwt-core/jwe/json"
+)
+
+// keyManagementParameters lists the header parameters carried by the key management payloads of Header.
+var keyManagementParameters = []string{"epk", "apu", "apv", "iv", "tag", "p2s", "p2c"}
+
+// Header is the JOSE header of a JWE. On top of the common parameters, it carries the parameters specific to
+// the key management algorithms. Those are set automatically on encryption.
+//
+// Parameters that are not modeled, such as private parameters, are kept in the Extra member of the embedded
+// jwa.JWH.
+//
+// https://datatracker.ietf.org/doc/html/rfc7516#section-4
+type Header struct {
+	jwa.JWH
+
+	// Parameters for ECDH-ES key agreement ("epk", "apu", "apv").
+	jwejson.ECDHKeyAgrPayload
+	// Parameters for AES GCM key encryption ("iv", "tag").
+	jwejson.AESGCMKeyEncPayload
+	// Parameters for PBES2 key encryption ("p2s", "p2c").
+	jwejson.PBES2KeyEncPayload
+}
+
+// keyManagementPayloads groups the key management parameters of a header. It has no method, unlike jwa.JWH.
+type keyManagementPayloads struct {
+	*jwejson.ECDHKeyAgrPayload
+	*jwejson.AESGCMKeyEncPayload
+	*jwejson.PBES2KeyEncPayload
+}
+
+// MarshalJSON serializes the header. It is required, because the marshaler of jwa.JWH would otherwise be promoted,
+// and ignore the key management parameters.
+func (header Header) MarshalJSON() ([]byte, error) {
+	// Key management parameters are not modeled by jwa.JWH, so they are passed to it as extra parameters.
+	payloads, err := members.Marshal(keyManagementPayloads{
+		&header.ECDHKeyAgrPayload, &header.AESGCMKeyEncPayload, &header.PBES2KeyEncPayload,
+	}, header.Extra, keyManagementParameters)
+	if err != nil {
+		return nil, err
+	}
+
+	jwh := header.JWH
+	if err = json.Unmarshal(payloads, &jwh.Extra); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jwh)
+}
+
+func (header *Header) UnmarshalJSON(data []byte) error {
+	var decoded Header
+
+	if err := json.Unmarshal(data, &decoded.JWH); err != nil {
+		return err
+	}
+
+	// Key management parameters are not modeled by jwa.JWH, so they end up in its extra parameters.
+	extra, err := json.Marshal(decoded.Extra)
+	if err != nil {
+		return err
+	}
+
+	decoded.Extra, err = members.Unmarshal(extra, &keyManagementPayloads{
+		&decoded.ECDHKeyAgrPayload, &decoded.AESGCMKeyEncPayload, &decoded.PBES2KeyEncPayload,
+	}, keyManagementParameters)
+	if err != nil {
+		return err
+	}
+
+	*header = decoded
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package jwecore_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwecore "github.com/a-novel-kit/jwt-core/jwe"
+	jwejson "github.com/a-novel-kit/jwt-core/jwe/json"
+)
+
+func TestHeader(t *testing.T) {
+	b64 := true
+
+	testCases := []struct {
+		name string
+
+		json string
+
+		expect      jwecore.Header
+		expectError bool
+	}{
+		{
+			name: "registered parameters",
+			json: `{"alg":"ECDH-ES+A128KW","enc":"A128GCM","kid":"key-1",` +
+				`"epk":{"kty":"EC","crv":"P-256","x":"eA","y":"eQ"},"apu":"dQ","apv":"dg"}`,
+			expect: jwecore.Header{
+				JWH: jwa.JWH{Alg: jwa.ECDHESA128KW, Enc: jwa.A128GCM, KID: "key-1"},
+				ECDHKeyAgrPayload: jwejson.ECDHKeyAgrPayload{
+					EPK: &jwejson.EPKPayload{KTY: jwa.KTYEC, Crv: "P-256", X: "eA", Y: "eQ"},
+					APU: "dQ",
+					APV: "dg",
+				},
+			},
+		},
+		{
+			name: "private parameters",
+			json: `{"alg":"PBES2-HS256+A128KW","enc":"A128GCM","p2s":"c2FsdA","p2c":1000,` +
+				`"tenant":"tenant-1","trace":{"id":[1,2]}}`,
+			expect: jwecore.Header{
+				JWH: jwa.JWH{
+					Alg: jwa.PBES2HS256A128KW,
+					Enc: jwa.A128GCM,
+					Extra: map[string]json.RawMessage{
+						"tenant": json.RawMessage(`"tenant-1"`),
+						"trace":  json.RawMessage(`{"id":[1,2]}`),
+					},
+				},
+				PBES2KeyEncPayload: jwejson.PBES2KeyEncPayload{P2S: "c2FsdA", P2C: 1000},
+			},
+		},
+		{
+			name: "AES GCM key encryption",
+			json: `{"alg":"A128GCMKW","enc":"A128GCM","iv":"aXY","tag":"dGFn","b64":true,"crit":["b64"]}`,
+			expect: jwecore.Header{
+				JWH:                 jwa.JWH{Alg: jwa.A128GCMKW, Enc: jwa.A128GCM, B64: &b64, Crit: []string{"b64"}},
+				AESGCMKeyEncPayload: jwejson.AESGCMKeyEncPayload{IV: "aXY", Tag: "dGFn"},
+			},
+		},
+		{
+			name:        "invalid parameter",
+			json:        `{"alg":"dir","p2c":"1000"}`,
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var header jwecore.Header
+
+			err := json.Unmarshal([]byte(testCase.json), &header)
+			if testCase.expectError {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.expect, header)
+
+			serialized, err := json.Marshal(header)
+			require.NoError(t, err)
+			require.JSONEq(t, testCase.json, string(serialized))
+		})
+	}
+}
+
+func TestHeaderMarshalConflict(t *testing.T) {
+	header := jwecore.Header{
+		JWH:                jwa.JWH{Alg: jwa.PBES2HS256A128KW, Extra: map[string]json.RawMessage{"p2c": json.RawMessage("1")}},
+		PBES2KeyEncPayload: jwejson.PBES2KeyEncPayload{P2C: 1000},
+	}
+
+	_, err := json.Marshal(header)
+	require.Error(t, err)
+
+	_, err = json.Marshal(jwa.JWH{Extra: map[string]json.RawMessage{"alg": json.RawMessage(`"none"`)}})
+	require.Error(t, err)
+
+	// Names that encoding/json would match to a modeled parameter are rejected too.
+	_, err = json.Marshal(jwa.JWH{Extra: map[string]json.RawMessage{"ALG": json.RawMessage(`"none"`)}})
+	require.Error(t, err)
+
+	_, err = json.Marshal(jwecore.Header{JWH: jwa.JWH{Extra: map[string]json.RawMessage{"P2C": json.RawMessage("1")}}})
+	require.Error(t, err)
+}
+
+func TestHeaderUnmarshalCaseVariant(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		data string
+	}{
+		{
+			name: "JWH parameter",
+			data: `{"ALG":"HS256"}`,
+		},
+		{
+			name: "JWH parameter with long s",
+			data: `{"alg":"dir","enc":"A128GCM","iſs":"foo"}`,
+		},
+		{
+			name: "key management parameter",
+			data: `{"alg":"ECDH-ES","enc":"A128GCM","Apu":"Zm9v"}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var header jwecore.Header
+			require.Error(t, json.Unmarshal([]byte(testCase.data), &header))
+		})
+	}
+}
@@ -0,0 +1,452 @@
+package jwecore
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+	"github.com/a-novel-kit/jwt-core/jwe/enc"
+	jwkcore "github.com/a-novel-kit/jwt-core/jwk"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+)
+
+var ErrNoMatchingRecipient = errors.New("no matching recipient")
+
+// JSONRecipient is a single recipient of a JWE in JSON serialization.
+//
+// https://datatracker.ietf.org/doc/html/rfc7516#section-7.2.1
+type JSONRecipient struct {
+	// Header is the per-recipient unprotected header. It carries the parameters set by the key management
+	// algorithm of the recipient ("epk", "p2s", "p2c", etc.).
+	Header *Header `json:"header,omitempty"`
+	// EncryptedKey is the base64url-encoded JWE Encrypted Key of the recipient.
+	EncryptedKey string `json:"encrypted_key,omitempty"`
+}
+
+// JSON is a JWE in JSON serialization. The content is encrypted once, and the content encryption key is
+// encrypted for each recipient.
+//
+// https://datatracker.ietf.org/doc/html/rfc7516#section-7.2
+type JSON struct {
+	// Protected is the base64url-encoded JWE Protected Header, shared by all recipients.
+	Protected string `json:"protected,omitempty"`
+	// Unprotected is the JWE Shared Unprotected Header.
+	Unprotected *Header `json:"unprotected,omitempty"`
+	// Recipients of the JWE.
+	Recipients []JSONRecipient `json:"recipients"`
+	// AAD is the base64url-encoded JWE AAD, an optional value integrity protected by the content encryption.
+	AAD string `json:"aad,omitempty"`
+	// IV is the base64url-encoded JWE Initialization Vector.
+	IV string `json:"iv,omitempty"`
+	// Ciphertext is the base64url-encoded JWE Ciphertext.
+	Ciphertext string `json:"ciphertext"`
+	// Tag is the base64url-encoded JWE Authentication Tag.
+	Tag string `json:"tag,omitempty"`
+}
+
+// JSONHeaders are the headers shared by all the recipients of a JWE in JSON serialization.
+type JSONHeaders struct {
+	// Protected is the header protected by the content encryption.
+	Protected *jwa.JWH
+	// Unprotected is the shared header that is not integrity protected.
+	Unprotected *jwa.JWH
+}
+
+// JSONRecipientKey describes one of the recipients of a JWE in JSON serialization.
+type JSONRecipientKey struct {
+	// Header is the per-recipient unprotected header. Its parameters must not overlap with the shared headers.
+	Header *jwa.JWH
+	// Key used to encrypt the content encryption key for the recipient. Its type must match the algorithm, as
+	// described in Encrypt.
+	Key any
+}
+
+// EncryptJSON creates a new JWE in JSON serialization, readable by each of the recipients. The key management
+// mode of each recipient is read from the "alg" parameter of its headers, and the content encryption algorithm
+// from the "enc" parameter, which must be the same for every recipient. The aad, if not empty, is integrity
+// protected along with the protected header. The "zip" parameter, if any, must be set in the protected header.
+//
+// Direct key agreement and direct encryption ("dir", "ECDH-ES") can only be used with a single recipient.
+func EncryptJSON(headers JSONHeaders, plaintext, aad []byte, recipients ...JSONRecipientKey) (*JSON, error) {
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("%w: at least one recipient is required", ErrNoMatchingRecipient)
+	}
+
+	output := &JSON{Recipients: make([]JSONRecipient, len(recipients))}
+
+	if headers.Unprotected != nil {
+		output.Unprotected = &Header{JWH: *headers.Unprotected}
+	}
+
+	if headers.Protected != nil {
+		var err error
+		if output.Protected, err = jwtcore.Encode(headers.Protected); err != nil {
+			return nil, fmt.Errorf("encode protected header: %w", err)
+		}
+	}
+
+	var (
+		encAlg jwa.Enc
+		zip    jwa.Zip
+		cek    []byte
+	)
+
+	if headers.Protected != nil {
+		zip = headers.Protected.Zip
+	}
+
+	for i, recipient := range recipients {
+		var fullHeader Header
+		if err := jwtcore.MergeHeaders(&fullHeader, headers.Protected, headers.Unprotected, recipient.Header); err != nil {
+			return nil, fmt.Errorf("recipient %d: %w", i, err)
+		}
+
+		// The "zip" parameter MUST be integrity protected; therefore, it MUST occur only within the JWE Protected
+		// Header.
+		//
+		// https://datatracker.ietf.org/doc/html/rfc7516#section-4.1.3
+		if fullHeader.Zip != zip {
+			return nil, fmt.Errorf("%w: zip must be set in the protected header", ErrUnsupportedZip)
+		}
+
+		if i == 0 {
+			encAlg = fullHeader.Enc
+		} else if fullHeader.Enc != encAlg {
+			return nil, fmt.Errorf(
+				"%w: recipient %d uses %q, expected %q", ErrUnsupportedEnc, i, fullHeader.Enc, encAlg,
+			)
+		}
+
+		var (
+			encryptedKey []byte
+			err          error
+		)
+
+		switch {
+		case len(recipients) == 1:
+			cek, encryptedKey, err = encryptKey(&fullHeader, recipient.Key)
+		case fullHeader.Alg == jwa.DIR || fullHeader.Alg == jwa.ECDHES:
+			err = fmt.Errorf("%w: %s cannot be used with multiple recipients", ErrUnsupportedAlg, fullHeader.Alg)
+		default:
+			if cek == nil {
+				var preset jwkgen.AESKeyPreset
+				if preset, err = contentPreset(encAlg); err != nil {
+					return nil, err
+				}
+
+				if cek, err = jwkgen.AES(preset.KeySize); err != nil {
+					return nil, fmt.Errorf("generate cek: %w", err)
+				}
+			}
+
+			encryptedKey, err = wrapKey(&fullHeader, cek, recipient.Key)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("recipient %d: encrypt key: %w", i, err)
+		}
+
+		// Parameters set by the key management algorithm are specific to the recipient.
+		recipientHeader := &Header{}
+		if recipient.Header != nil {
+			recipientHeader.JWH = *recipient.Header
+		}
+
+		recipientHeader.EPK = fullHeader.EPK
+		recipientHeader.AESGCMKeyEncPayload = fullHeader.AESGCMKeyEncPayload
+		recipientHeader.PBES2KeyEncPayload = fullHeader.PBES2KeyEncPayload
+
+		output.Recipients[i] = JSONRecipient{
+			Header:       recipientHeader,
+			EncryptedKey: base64.RawURLEncoding.EncodeToString(encryptedKey),
+		}
+	}
+
+	compressed, err := compress(zip, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("compress plaintext: %w", err)
+	}
+
+	preset, err := contentPreset(encAlg)
+	if err != nil {
+		return nil, err
+	}
+
+	iv, err := jwkgen.IV(preset.IVSize)
+	if err != nil {
+		return nil, fmt.Errorf("generate iv: %w", err)
+	}
+
+	if len(aad) > 0 {
+		output.AAD = base64.RawURLEncoding.EncodeToString(aad)
+	}
+
+	encrypted, err := encryptContent(encAlg, compressed, output.additionalData(), &jwkcore.AESKeySet{CEK: cek, IV: iv})
+	if err != nil {
+		return nil, fmt.Errorf("encrypt content: %w", err)
+	}
+
+	output.IV = base64.RawURLEncoding.EncodeToString(iv)
+	output.Ciphertext = base64.RawURLEncoding.EncodeToString(encrypted.E)
+	output.Tag = base64.RawURLEncoding.EncodeToString(encrypted.T)
+
+	return output, nil
+}
+
+// ParseJSON reads a JWE in either the general or the flattened JSON serialization. It does not decrypt the
+// content: use JSON.Decrypt for this purpose.
+//
+// https://datatracker.ietf.org/doc/html/rfc7516#section-7.2.2
+func ParseJSON(data []byte) (*JSON, error) {
+	return ParseJSONWithConfig(data, nil)
+}
+
+// ParseJSONWithConfig works like ParseJSON, with the size limits of a custom configuration. A nil configuration
+// uses the defaults.
+func ParseJSONWithConfig(data []byte, config *DecryptConfig) (*JSON, error) {
+	limits := config.limits()
+
+	if err := limits.CheckTokenSize(len(data)); err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		JSON
+
+		// Members of the flattened serialization.
+		Header       *Header `json:"header"`
+		EncryptedKey string  `json:"encrypted_key"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	if raw.Ciphertext == "" {
+		return nil, fmt.Errorf("%w: missing ciphertext", ErrMalformedToken)
+	}
+
+	flattened := raw.Header != nil || raw.EncryptedKey != ""
+
+	if raw.Recipients != nil && flattened {
+		return nil, fmt.Errorf("%w: recipients must not be mixed with a flattened recipient", ErrMalformedToken)
+	}
+
+	output := raw.JSON
+	if flattened || output.Recipients == nil {
+		// A flattened JWE with direct encryption has no recipient specific member.
+		output.Recipients = []JSONRecipient{{Header: raw.Header, EncryptedKey: raw.EncryptedKey}}
+	}
+
+	if len(output.Recipients) == 0 {
+		return nil, fmt.Errorf("%w: missing recipients", ErrMalformedToken)
+	}
+
+	if err := limits.CheckHeaderSize(len(output.Protected)); err != nil {
+		return nil, err
+	}
+
+	if err := limits.CheckPayloadSize(len(output.Ciphertext)); err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+// MarshalFlattened returns the flattened JSON serialization of the JWE. It only works for a JWE with a single
+// recipient.
+//
+// https://datatracker.ietf.org/doc/html/rfc7516#section-7.2.2
+func (doc *JSON) MarshalFlattened() ([]byte, error) {
+	if len(doc.Recipients) != 1 {
+		return nil, fmt.Errorf(
+			"%w: flattened serialization requires exactly 1 recipient, got %d", ErrMalformedToken, len(doc.Recipients),
+		)
+	}
+
+	return json.Marshal(struct {
+		Protected   string  `json:"protected,omitempty"`
+		Unprotected *Header `json:"unprotected,omitempty"`
+		JSONRecipient
+		AAD        string `json:"aad,omitempty"`
+		IV         string `json:"iv,omitempty"`
+		Ciphertext string `json:"ciphertext"`
+		Tag        string `json:"tag,omitempty"`
+	}{
+		Protected:     doc.Protected,
+		Unprotected:   doc.Unprotected,
+		JSONRecipient: doc.Recipients[0],
+		AAD:           doc.AAD,
+		IV:            doc.IV,
+		Ciphertext:    doc.Ciphertext,
+		Tag:           doc.Tag,
+	})
+}
+
+// Decrypt looks for a recipient that can be decrypted with the given key, and returns the decrypted plaintext.
+// Every recipient is tried in order, until one succeeds.
+func (doc *JSON) Decrypt(key any) ([]byte, error) {
+	return doc.DecryptWithConfig(key, nil)
+}
+
+// DecryptWithConfig works like Decrypt, with a custom configuration. A nil configuration uses the defaults.
+func (doc *JSON) DecryptWithConfig(key any, config *DecryptConfig) ([]byte, error) {
+	errs := []error{ErrNoMatchingRecipient}
+
+	for i := range doc.Recipients {
+		plaintext, err := doc.decryptRecipient(i, key, config)
+		if err == nil {
+			return plaintext, nil
+		}
+
+		errs = append(errs, fmt.Errorf("recipient %d: %w", i, err))
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// RecipientHeader returns the JOSE header of a recipient, which is the union of the protected, shared
+// unprotected, and per-recipient headers.
+//
+// https://datatracker.ietf.org/doc/html/rfc7516#section-7.2.1
+func (doc *JSON) RecipientHeader(index int) (*Header, error) {
+	if index < 0 || index >= len(doc.Recipients) {
+		return nil, fmt.Errorf("%w: recipient %d does not exist", ErrNoMatchingRecipient, index)
+	}
+
+	protected, err := doc.protectedHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	var header Header
+	if err := jwtcore.MergeHeaders(&header, protected, doc.Unprotected, doc.Recipients[index].Header); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	if header.Alg == "" || header.Enc == "" {
+		return nil, fmt.Errorf("%w: missing alg or enc header", ErrMalformedToken)
+	}
+
+	return &header, nil
+}
+
+func (doc *JSON) decryptRecipient(index int, key any, config *DecryptConfig) ([]byte, error) {
+	header, err := doc.RecipientHeader(index)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = doc.checkCritical(index, config); err != nil {
+		return nil, err
+	}
+
+	if header.Zip != "" {
+		if header.Zip != jwa.ZipDeflate {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedZip, header.Zip)
+		}
+
+		// Only trust a compression algorithm that is integrity protected.
+		protected, err := doc.protectedHeader()
+		if err != nil {
+			return nil, err
+		}
+
+		if protected == nil || protected.Zip != header.Zip {
+			return nil, fmt.Errorf("%w: zip must be set in the protected header", ErrUnsupportedZip)
+		}
+	}
+
+	segments := make([][]byte, 4)
+	for i, segment := range []struct{ name, value string }{
+		{"encrypted key", doc.Recipients[index].EncryptedKey},
+		{"iv", doc.IV},
+		{"ciphertext", doc.Ciphertext},
+		{"tag", doc.Tag},
+	} {
+		if segments[i], err = jwtcore.DecodeSegment(segment.value); err != nil {
+			return nil, fmt.Errorf("%w: decode %s: %w", ErrMalformedToken, segment.name, err)
+		}
+	}
+
+	cek, err := decryptKey(header, segments[0], key)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt key: %w", err)
+	}
+
+	plaintext, err := decryptContent(
+		header.Enc,
+		&enc.AESPayload{E: segments[2], T: segments[3]},
+		doc.additionalData(),
+		&jwkcore.AESKeySet{CEK: cek, IV: segments[1]},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt content: %w", err)
+	}
+
+	plaintext, err = decompress(header.Zip, plaintext, config)
+	if err != nil {
+		return nil, fmt.Errorf("decompress plaintext: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// checkCritical validates the "crit" header of a recipient.
+//
+// https://datatracker.ietf.org/doc/html/rfc7516#section-4.1.13
+//
+// This parameter has the same meaning, syntax, and processing rules as
+// the "crit" Header Parameter defined in Section 4.1.11 of [JWS],
+// except that Header Parameters for a JWE are being referred to, rather
+// than Header Parameters for a JWS.
+func (doc *JSON) checkCritical(index int, config *DecryptConfig) error {
+	// When used, "crit" must be integrity protected.
+	for _, header := range []*Header{doc.Unprotected, doc.Recipients[index].Header} {
+		if header != nil && header.Crit != nil {
+			return fmt.Errorf("%w: crit must be set in the protected header", jwtcore.ErrInvalidCritical)
+		}
+	}
+
+	if doc.Protected == "" {
+		return nil
+	}
+
+	if err := jwtcore.CheckCritical(doc.Protected, config.critical()); err != nil {
+		return fmt.Errorf("check critical header: %w", err)
+	}
+
+	return nil
+}
+
+func (doc *JSON) protectedHeader() (*Header, error) {
+	if doc.Protected == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	var protected Header
+	if err := jwtcore.DecodeStrict(doc.Protected, &protected); err != nil {
+		return nil, fmt.Errorf("%w: decode protected header: %w", ErrMalformedToken, err)
+	}
+
+	return &protected, nil
+}
+
+// additionalData computes the Additional Authenticated Data of the content encryption.
+//
+// https://datatracker.ietf.org/doc/html/rfc7516#section-5.1
+//
+// Let the Additional Authenticated Data encryption parameter be ASCII(Encoded Protected Header). However, if a
+// JWE AAD value is present (which can only be the case when using the JWE JSON Serialization), instead let the
+// Additional Authenticated Data encryption parameter be ASCII(Encoded Protected Header || '.' ||
+// BASE64URL(JWE AAD)).
+func (doc *JSON) additionalData() []byte {
+	if doc.AAD == "" {
+		return []byte(doc.Protected)
+	}
+
+	return []byte(jwtcore.Assemble(doc.Protected, doc.AAD))
+}
@@ -13,7 +13,7 @@ type AESGCMKeyEncPayload struct {
 	// key encryption operation. This Header Parameter MUST be present and
 	// MUST be understood and processed by implementations when these
 	// algorithms are used.
-	IV string `json:"iv"`
+	IV string `json:"iv,omitempty"`
 	// Tag (Authentication Tag) Header Parameter.
 	//
 	// https://datatracker.ietf.org/doc/html/rfc7518#section-4.7.1.2
@@ -23,5 +23,5 @@ type AESGCMKeyEncPayload struct {
 	// value resulting from the key encryption operation. This Header
 	// Parameter MUST be present and MUST be understood and processed by
 	// implementations when these algorithms are used.
-	Tag string `json:"tag"`
+	Tag string `json:"tag,omitempty"`
 }
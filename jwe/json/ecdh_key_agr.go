@@ -1,6 +1,16 @@
 package jwejson
 
-import jwkjson "github.com/a-novel-kit/jwt-core/jwk/json"
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwkjson "github.com/a-novel-kit/jwt-core/jwk/json"
+)
+
+var ErrUnsupportedEPK = errors.New("unsupported ephemeral public key")
 
 // ECDHKeyAgrPayload represents the ECDH-ES key agreement algorithm header parameters.
 //
@@ -18,7 +28,7 @@ type ECDHKeyAgrPayload struct {
 	// checked for consistency and honored, or they can be ignored. This
 	// Header Parameter MUST be present and MUST be understood and processed
 	// by implementations when these algorithms are used.
-	EPK *jwkjson.ECDHPayload `json:"epk"`
+	EPK *EPKPayload `json:"epk,omitempty"`
 	// APU (Agreement PartyUInfo) Header Parameter.
 	//
 	// https://datatracker.ietf.org/doc/html/rfc7518#section-4.6.1.2
@@ -42,3 +52,62 @@ type ECDHKeyAgrPayload struct {
 	// when these algorithms are used.
 	APV string `json:"apv,omitempty"`
 }
+
+// EPKPayload is the JWK representation of an ephemeral public key. It holds either an elliptic curve key
+// ("kty": "EC"), or an X25519 key ("kty": "OKP").
+//
+// https://datatracker.ietf.org/doc/html/rfc7518#section-4.6.1.1
+// https://datatracker.ietf.org/doc/html/rfc8037#section-3.2
+type EPKPayload struct {
+	// KTY is the key type, either "EC" or "OKP".
+	KTY jwa.KTY `json:"kty"`
+	// Crv (curve) parameter.
+	Crv string `json:"crv"`
+	// X coordinate parameter.
+	X string `json:"x"`
+	// Y coordinate parameter. Only present for EC keys.
+	Y string `json:"y,omitempty"`
+}
+
+// EncodeEPK returns the JWK representation of an ephemeral public key.
+func EncodeEPK[Key *ecdsa.PublicKey | *ecdh.PublicKey](key Key) (*EPKPayload, error) {
+	ecKey, ok := any(key).(*ecdsa.PublicKey)
+	if ok {
+		payload, err := jwkjson.EncodeEC(ecKey)
+		if err != nil {
+			return nil, fmt.Errorf("encode ec key: %w", err)
+		}
+
+		return &EPKPayload{KTY: jwa.KTYEC, Crv: payload.Crv, X: payload.X, Y: payload.Y}, nil
+	}
+
+	payload, err := jwkjson.EncodeECDH(any(key).(*ecdh.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("encode ecdh key: %w", err)
+	}
+
+	return &EPKPayload{KTY: jwa.KTYOKP, Crv: payload.Crv, X: payload.X}, nil
+}
+
+// DecodeEPK decodes the ephemeral public key from its JWK representation. The returned key is either a
+// *ecdsa.PublicKey or a *ecdh.PublicKey, depending on the key type.
+func DecodeEPK(src *EPKPayload) (any, error) {
+	switch src.KTY {
+	case jwa.KTYEC:
+		_, pubKey, err := jwkjson.DecodeEC(&jwkjson.ECPayload{Crv: src.Crv, X: src.X, Y: src.Y})
+		if err != nil {
+			return nil, fmt.Errorf("decode ec key: %w", err)
+		}
+
+		return pubKey, nil
+	case jwa.KTYOKP:
+		_, pubKey, err := jwkjson.DecodeECDH(&jwkjson.ECDHPayload{Crv: src.Crv, X: src.X})
+		if err != nil {
+			return nil, fmt.Errorf("decode ecdh key: %w", err)
+		}
+
+		return pubKey, nil
+	default:
+		return nil, fmt.Errorf("%w: kty %q", ErrUnsupportedEPK, src.KTY)
+	}
+}
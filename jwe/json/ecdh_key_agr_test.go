@@ -0,0 +1,49 @@
+package jwejson_test
+
+import (
+	"crypto/elliptic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwejson "github.com/a-novel-kit/jwt-core/jwe/json"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+)
+
+func TestEncodeDecodeEPK(t *testing.T) {
+	t.Run("EC", func(t *testing.T) {
+		key, err := jwkgen.EC(elliptic.P384())
+		require.NoError(t, err)
+
+		encoded, err := jwejson.EncodeEPK(&key.PublicKey)
+		require.NoError(t, err)
+		require.Equal(t, jwa.KTYEC, encoded.KTY)
+		require.Equal(t, "P-384", encoded.Crv)
+		require.NotEmpty(t, encoded.Y)
+
+		decoded, err := jwejson.DecodeEPK(encoded)
+		require.NoError(t, err)
+		require.True(t, key.PublicKey.Equal(decoded))
+	})
+
+	t.Run("X25519", func(t *testing.T) {
+		key, err := jwkgen.X25519()
+		require.NoError(t, err)
+
+		encoded, err := jwejson.EncodeEPK(key.PublicKey())
+		require.NoError(t, err)
+		require.Equal(t, jwa.KTYOKP, encoded.KTY)
+		require.Equal(t, "X25519", encoded.Crv)
+		require.Empty(t, encoded.Y)
+
+		decoded, err := jwejson.DecodeEPK(encoded)
+		require.NoError(t, err)
+		require.True(t, key.PublicKey().Equal(decoded))
+	})
+
+	t.Run("unsupported key type", func(t *testing.T) {
+		_, err := jwejson.DecodeEPK(&jwejson.EPKPayload{KTY: jwa.KTYRSA})
+		require.ErrorIs(t, err, jwejson.ErrUnsupportedEPK)
+	})
+}
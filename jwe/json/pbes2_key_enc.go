@@ -21,7 +21,7 @@ type PBES2KeyEncPayload struct {
 	// generating random values. The salt value used is (UTF8(Alg) || 0x00
 	// || Salt Input), where Alg is the "alg" (algorithm) Header Parameter
 	// value.
-	P2S string `json:"p2s"`
+	P2S string `json:"p2s,omitempty"`
 	// P2C (PBES2 Count) Header Parameter.
 	//
 	// https://datatracker.ietf.org/doc/html/rfc7518#section-4.8.1.2
@@ -34,5 +34,5 @@ type PBES2KeyEncPayload struct {
 	// The iteration count adds computational expense, ideally compounded by
 	// the possible range of keys introduced by the salt. A minimum
 	// iteration count of 1000 is RECOMMENDED.
-	P2C int `json:"p2c"`
+	P2C int `json:"p2c,omitempty"`
 }
@@ -0,0 +1,278 @@
+package jwecore_test
+
+import (
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwecore "github.com/a-novel-kit/jwt-core/jwe"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+)
+
+func TestEncryptJSONAndDecrypt(t *testing.T) {
+	rsaKey, err := jwkgen.RSA(jwkgen.RS256KeySize)
+	require.NoError(t, err)
+
+	ecKey, err := jwkgen.EC(elliptic.P256())
+	require.NoError(t, err)
+
+	kek256, err := jwkgen.AES(jwkgen.AESKeySize256)
+	require.NoError(t, err)
+
+	otherKEK, err := jwkgen.AES(jwkgen.AESKeySize256)
+	require.NoError(t, err)
+
+	plaintext := []byte("I will be notified.")
+	aad := []byte("broadcast")
+
+	doc, err := jwecore.EncryptJSON(
+		jwecore.JSONHeaders{
+			Protected:   &jwa.JWH{Enc: jwa.A256GCM},
+			Unprotected: &jwa.JWH{JKU: "https://server.example.com/keys.jwks"},
+		},
+		plaintext,
+		aad,
+		jwecore.JSONRecipientKey{Header: &jwa.JWH{Alg: jwa.RSAOAEP, KID: "rsa"}, Key: &rsaKey.PublicKey},
+		jwecore.JSONRecipientKey{Header: &jwa.JWH{Alg: jwa.ECDHESA256KW, KID: "ec"}, Key: &ecKey.PublicKey},
+		jwecore.JSONRecipientKey{Header: &jwa.JWH{Alg: jwa.A256KW, KID: "aes"}, Key: kek256},
+	)
+	require.NoError(t, err)
+	require.Len(t, doc.Recipients, 3)
+	require.NotNil(t, doc.Recipients[1].Header.EPK)
+
+	serialized, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	parsed, err := jwecore.ParseJSON(serialized)
+	require.NoError(t, err)
+
+	t.Run("recipients", func(t *testing.T) {
+		for _, key := range []any{rsaKey, ecKey, kek256} {
+			decrypted, err := parsed.Decrypt(key)
+			require.NoError(t, err)
+			require.Equal(t, plaintext, decrypted)
+		}
+	})
+
+	t.Run("recipient header", func(t *testing.T) {
+		header, err := parsed.RecipientHeader(2)
+		require.NoError(t, err)
+		require.Equal(t, jwa.JWH{
+			Alg: jwa.A256KW,
+			Enc: jwa.A256GCM,
+			JKU: "https://server.example.com/keys.jwks",
+			KID: "aes",
+		}, header.JWH)
+
+		_, err = parsed.RecipientHeader(3)
+		require.ErrorIs(t, err, jwecore.ErrNoMatchingRecipient)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := parsed.Decrypt(otherKEK)
+		require.ErrorIs(t, err, jwecore.ErrNoMatchingRecipient)
+	})
+
+	t.Run("tampered aad", func(t *testing.T) {
+		tampered := *parsed
+		tampered.AAD = base64.RawURLEncoding.EncodeToString([]byte("unicast"))
+
+		_, err := tampered.Decrypt(kek256)
+		require.ErrorIs(t, err, jwecore.ErrNoMatchingRecipient)
+	})
+
+	t.Run("tampered protected header", func(t *testing.T) {
+		protected, err := jwtcore.Encode(jwa.JWH{Enc: jwa.A256GCM, KID: "aes"})
+		require.NoError(t, err)
+
+		tampered := *parsed
+		tampered.Protected = protected
+
+		_, err = tampered.Decrypt(kek256)
+		require.ErrorIs(t, err, jwecore.ErrNoMatchingRecipient)
+	})
+}
+
+func TestEncryptJSONFlattened(t *testing.T) {
+	x25519Key, err := jwkgen.X25519()
+	require.NoError(t, err)
+
+	plaintext := []byte("Live long and prosper.")
+
+	doc, err := jwecore.EncryptJSON(
+		jwecore.JSONHeaders{Protected: &jwa.JWH{Alg: jwa.ECDHES, Enc: jwa.A128CBC}},
+		plaintext,
+		nil,
+		jwecore.JSONRecipientKey{Key: x25519Key.PublicKey()},
+	)
+	require.NoError(t, err)
+
+	serialized, err := doc.MarshalFlattened()
+	require.NoError(t, err)
+	require.NotContains(t, string(serialized), `"recipients"`)
+	require.NotContains(t, string(serialized), `"encrypted_key"`)
+
+	parsed, err := jwecore.ParseJSON(serialized)
+	require.NoError(t, err)
+	require.Equal(t, doc, parsed)
+
+	decrypted, err := parsed.Decrypt(x25519Key)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, decrypted)
+}
+
+func TestDecryptJSONVector(t *testing.T) {
+	// https://datatracker.ietf.org/doc/html/rfc7516#appendix-A.4
+	key, err := base64.RawURLEncoding.DecodeString("GawgguFyGrWKav7AX4VKUg")
+	require.NoError(t, err)
+
+	data := `{
+		"protected":"eyJlbmMiOiJBMTI4Q0JDLUhTMjU2In0",
+		"unprotected":{"jku":"https://server.example.com/keys.jwks"},
+		"recipients":[
+			{
+				"header":{"alg":"RSA1_5","kid":"2011-04-29"},
+				"encrypted_key":"UGhIOguC7IuEvf_NPVaXsGMoLOmwvc1GyqlIKOK1nN94nHPoltGRhWhw7Zx0-kFm1NJn8LE9XShH59_i8J0PH5ZZyNfGy2xGdULU7sHNF6Gp2vPLgNZ__deLKxGHZ7PcHALUzoOegEI-8E66jX2E4zyJKx-YxzZIItRzC5hlRirb6Y5Cl_p-ko3YvkkysZIFNPccxRU7qve1WYPxqbb2Yw8kZqa2rMWI5ng8OtvzlV7elprCbuPhcCdZ6XDP0_F8rkXds2vE4X-ncOIM8hAYHHi29NX0mcKiRaD0-D-ljQTP-cFPgwCp6X-nZZd9OHBv-B3oWh2TbqmScqXMR4gp_A"
+			},
+			{
+				"header":{"alg":"A128KW","kid":"7"},
+				"encrypted_key":"6KB707dM9YTIgHtLvtgWQ8mKwboJW3of9locizkDTHzBC2IlrT1oOQ"
+			}
+		],
+		"iv":"AxY8DCtDaGlsbGljb3RoZQ",
+		"ciphertext":"KDlTtXchhZTGufMYmOYGS4HffxPSUrfmqCHXaI9wOGY",
+		"tag":"Mz-VPPyU4RlcuYv1IwIvzw"
+	}`
+
+	parsed, err := jwecore.ParseJSON([]byte(data))
+	require.NoError(t, err)
+
+	decrypted, err := parsed.Decrypt(key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("Live long and prosper."), decrypted)
+}
+
+func TestEncryptJSON(t *testing.T) {
+	kek128, err := jwkgen.AES(jwkgen.AESKeySize128)
+	require.NoError(t, err)
+
+	cek256, err := jwkgen.AES(jwkgen.AESKeySize256)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		headers    jwecore.JSONHeaders
+		recipients []jwecore.JSONRecipientKey
+
+		expect error
+	}{
+		{
+			name: "no recipients",
+
+			headers: jwecore.JSONHeaders{Protected: &jwa.JWH{Enc: jwa.A128GCM}},
+
+			expect: jwecore.ErrNoMatchingRecipient,
+		},
+		{
+			name: "duplicate header parameter",
+
+			headers: jwecore.JSONHeaders{Protected: &jwa.JWH{Enc: jwa.A128GCM, KID: "key"}},
+			recipients: []jwecore.JSONRecipientKey{
+				{Header: &jwa.JWH{Alg: jwa.A128KW, KID: "key"}, Key: kek128},
+			},
+
+			expect: jwtcore.ErrDuplicateHeader,
+		},
+		{
+			name: "mismatching enc",
+
+			headers: jwecore.JSONHeaders{Protected: &jwa.JWH{Alg: jwa.A128KW}},
+			recipients: []jwecore.JSONRecipientKey{
+				{Header: &jwa.JWH{Enc: jwa.A128GCM}, Key: kek128},
+				{Header: &jwa.JWH{Enc: jwa.A256GCM}, Key: kek128},
+			},
+
+			expect: jwecore.ErrUnsupportedEnc,
+		},
+		{
+			name: "direct encryption with multiple recipients",
+
+			headers: jwecore.JSONHeaders{Protected: &jwa.JWH{Enc: jwa.A128GCM}},
+			recipients: []jwecore.JSONRecipientKey{
+				{Header: &jwa.JWH{Alg: jwa.A128KW}, Key: kek128},
+				{Header: &jwa.JWH{Alg: jwa.DIR}, Key: cek256},
+			},
+
+			expect: jwecore.ErrUnsupportedAlg,
+		},
+		{
+			name: "invalid key",
+
+			headers: jwecore.JSONHeaders{Protected: &jwa.JWH{Alg: jwa.A128KW, Enc: jwa.A128GCM}},
+			recipients: []jwecore.JSONRecipientKey{
+				{Key: cek256},
+			},
+
+			expect: jwecore.ErrInvalidKey,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := jwecore.EncryptJSON(testCase.headers, []byte("plaintext"), nil, testCase.recipients...)
+			require.ErrorIs(t, err, testCase.expect)
+		})
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		data string
+
+		expect error
+	}{
+		{
+			name: "missing ciphertext",
+
+			data: `{"protected":"eyJlbmMiOiJBMTI4Q0JDLUhTMjU2In0","recipients":[{"encrypted_key":"a2V5"}]}`,
+
+			expect: jwecore.ErrMalformedToken,
+		},
+		{
+			name: "empty recipients",
+
+			data: `{"recipients":[],"ciphertext":"Y2lwaGVy"}`,
+
+			expect: jwecore.ErrMalformedToken,
+		},
+		{
+			name: "mixed serializations",
+
+			data: `{"recipients":[{"encrypted_key":"a2V5"}],"encrypted_key":"a2V5","ciphertext":"Y2lwaGVy"}`,
+
+			expect: jwecore.ErrMalformedToken,
+		},
+		{
+			name: "invalid JSON",
+
+			data: `{"ciphertext":`,
+
+			expect: jwecore.ErrMalformedToken,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := jwecore.ParseJSON([]byte(testCase.data))
+			require.ErrorIs(t, err, testCase.expect)
+		})
+	}
+}
@@ -0,0 +1,423 @@
+package jwecore
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwejson "github.com/a-novel-kit/jwt-core/jwe/json"
+	"github.com/a-novel-kit/jwt-core/jwe/keyagr"
+	"github.com/a-novel-kit/jwt-core/jwe/keyenc"
+	"github.com/a-novel-kit/jwt-core/jwe/keywrap"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+)
+
+const (
+	// PBES2DefaultCount is the iteration count used by PBES2 key encryption.
+	PBES2DefaultCount = 100_000
+	// PBES2MaxCount is the highest iteration count accepted when decrypting a PBES2 key. It prevents a token from
+	// forcing the recipient to run an arbitrary amount of PBKDF2 iterations.
+	PBES2MaxCount = 1_000_000
+
+	// pbes2SaltSize is the size of the "p2s" salt input.
+	pbes2SaltSize = 16
+	// pbes2MinSaltSize is the minimum size of the "p2s" salt input accepted when decrypting a PBES2 key.
+	//
+	// https://datatracker.ietf.org/doc/html/rfc7518#section-4.8.1.1
+	pbes2MinSaltSize = 8
+)
+
+// kwKeySizes maps AES key wrapping algorithms to the size of their key encryption key.
+var kwKeySizes = map[jwa.Alg]int{
+	jwa.A128KW: int(jwkgen.AESKeySize128),
+	jwa.A192KW: int(jwkgen.AESKeySize192),
+	jwa.A256KW: int(jwkgen.AESKeySize256),
+}
+
+// ecdhKWAlgs maps ECDH-ES key wrapping algorithms to the key agreement output.
+var ecdhKWAlgs = map[jwa.Alg]keyagr.Alg{
+	jwa.ECDHESA128KW: keyagr.AlgA128KW,
+	jwa.ECDHESA192KW: keyagr.AlgA192KW,
+	jwa.ECDHESA256KW: keyagr.AlgA256KW,
+}
+
+// ecdhDirectAlgs maps content encryption algorithms to the output of a direct ECDH-ES key agreement.
+var ecdhDirectAlgs = map[jwa.Enc]keyagr.Alg{
+	jwa.A128CBC: keyagr.AlgA128CBC,
+	jwa.A192CBC: keyagr.AlgA192CBC,
+	jwa.A256CBC: keyagr.AlgA256CBC,
+	jwa.A128GCM: keyagr.AlgA128GCM,
+	jwa.A192GCM: keyagr.AlgA192GCM,
+	jwa.A256GCM: keyagr.AlgA256GCM,
+}
+
+// pbes2Hashes maps PBES2 algorithms to the hash used by the PBKDF2 derivation.
+var pbes2Hashes = map[jwa.Alg]crypto.Hash{
+	jwa.PBES2HS256A128KW: crypto.SHA256,
+	jwa.PBES2HS384A192KW: crypto.SHA384,
+	jwa.PBES2HS512A256KW: crypto.SHA512,
+}
+
+// encryptKey runs the key management algorithm on the issuer side. It returns the CEK, and its encrypted
+// representation for the recipient.
+//
+// Parameters required by the algorithm to decrypt the key (such as "epk" or "p2s") are set on the header.
+func encryptKey(header *Header, key any) ([]byte, []byte, error) {
+	preset, err := contentPreset(header.Enc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch header.Alg {
+	case jwa.DIR:
+		// https://datatracker.ietf.org/doc/html/rfc7518#section-4.5
+		cek, ok := key.([]byte)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s expects []byte, got %T", ErrInvalidKey, header.Alg, key)
+		}
+
+		if len(cek) != int(preset.KeySize) {
+			return nil, nil, fmt.Errorf("%w: %s expects a %d bytes key", ErrInvalidKey, header.Enc, preset.KeySize)
+		}
+
+		return cek, nil, nil
+	case jwa.ECDHES:
+		// https://datatracker.ietf.org/doc/html/rfc7518#section-4.6
+		cek, err := agreeKey(header, key, ecdhDirectAlgs[header.Enc])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return cek, nil, nil
+	}
+
+	cek, err := jwkgen.AES(preset.KeySize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate cek: %w", err)
+	}
+
+	encryptedKey, err := wrapKey(header, cek, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, encryptedKey, nil
+}
+
+// wrapKey encrypts a CEK for a recipient, using one of the key management algorithms that produce a
+// JWE Encrypted Key.
+func wrapKey(header *Header, cek []byte, key any) ([]byte, error) {
+	switch header.Alg {
+	case jwa.RSA15, jwa.RSAOAEP, jwa.RSAOAEP256:
+		// https://datatracker.ietf.org/doc/html/rfc7518#section-4.2
+		// https://datatracker.ietf.org/doc/html/rfc7518#section-4.3
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s expects *rsa.PublicKey, got %T", ErrInvalidKey, header.Alg, key)
+		}
+
+		switch header.Alg {
+		case jwa.RSA15:
+			return keyenc.EncryptRSAESPKCS1V15(rsaKey, cek) //nolint:staticcheck
+		case jwa.RSAOAEP:
+			return keyenc.EncryptRSAESOAEP(rsaKey, sha1.New(), cek)
+		default:
+			return keyenc.EncryptRSAESOAEP(rsaKey, sha256.New(), cek)
+		}
+	case jwa.A128KW, jwa.A192KW, jwa.A256KW:
+		// https://datatracker.ietf.org/doc/html/rfc7518#section-4.4
+		kek, ok := key.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s expects []byte, got %T", ErrInvalidKey, header.Alg, key)
+		}
+
+		if len(kek) != kwKeySizes[header.Alg] {
+			return nil, fmt.Errorf("%w: %s expects a %d bytes key", ErrInvalidKey, header.Alg, kwKeySizes[header.Alg])
+		}
+
+		return keywrap.WrapAES(kek, cek)
+	case jwa.A128GCMKW, jwa.A192GCMKW, jwa.A256GCMKW:
+		// https://datatracker.ietf.org/doc/html/rfc7518#section-4.7
+		kek, ok := key.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s expects []byte, got %T", ErrInvalidKey, header.Alg, key)
+		}
+
+		encryptedKey, params, err := keywrap.WrapAESGCM(header.Alg, kek, cek)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+		}
+
+		header.AESGCMKeyEncPayload = *params
+
+		return encryptedKey, nil
+	case jwa.ECDHESA128KW, jwa.ECDHESA192KW, jwa.ECDHESA256KW:
+		// https://datatracker.ietf.org/doc/html/rfc7518#section-4.6
+		kek, err := agreeKey(header, key, ecdhKWAlgs[header.Alg])
+		if err != nil {
+			return nil, err
+		}
+
+		return keywrap.WrapAES(kek, cek)
+	case jwa.PBES2HS256A128KW, jwa.PBES2HS384A192KW, jwa.PBES2HS512A256KW:
+		// https://datatracker.ietf.org/doc/html/rfc7518#section-4.8
+		password, ok := key.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s expects []byte, got %T", ErrInvalidKey, header.Alg, key)
+		}
+
+		saltInput := make([]byte, pbes2SaltSize)
+		if _, err := rand.Read(saltInput); err != nil {
+			return nil, fmt.Errorf("generate salt input: %w", err)
+		}
+
+		header.P2S = base64.RawURLEncoding.EncodeToString(saltInput)
+		header.P2C = PBES2DefaultCount
+
+		kek, err := keyenc.DerivePBES2(pbes2Hashes[header.Alg], pbes2Salt(header.Alg, saltInput), password, header.P2C)
+		if err != nil {
+			return nil, fmt.Errorf("derive kek: %w", err)
+		}
+
+		return keywrap.WrapAES(kek, cek)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlg, header.Alg)
+	}
+}
+
+// agreeKey generates an ephemeral key pair, and derives a key from it and the recipient public key. The
+// ephemeral public key is set on the header.
+func agreeKey(header *Header, key any, out keyagr.Alg) ([]byte, error) {
+	apu, apv, err := decodeAgreementInfo(header)
+	if err != nil {
+		return nil, err
+	}
+
+	switch recipientKey := key.(type) {
+	case *ecdsa.PublicKey:
+		ephemeralKey, err := jwkgen.EC(recipientKey.Curve)
+		if err != nil {
+			return nil, fmt.Errorf("generate ephemeral key: %w", err)
+		}
+
+		header.EPK, err = jwejson.EncodeEPK(&ephemeralKey.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("encode ephemeral key: %w", err)
+		}
+
+		return keyagr.DeriveECDHES(ephemeralKey, recipientKey, out, apu, apv)
+	case *ecdh.PublicKey:
+		if recipientKey.Curve() != ecdh.X25519() {
+			return nil, fmt.Errorf("%w: %s only supports X25519 keys", ErrInvalidKey, header.Alg)
+		}
+
+		ephemeralKey, err := jwkgen.X25519()
+		if err != nil {
+			return nil, fmt.Errorf("generate ephemeral key: %w", err)
+		}
+
+		header.EPK, err = jwejson.EncodeEPK(ephemeralKey.PublicKey())
+		if err != nil {
+			return nil, fmt.Errorf("encode ephemeral key: %w", err)
+		}
+
+		return keyagr.DeriveECDHED(ephemeralKey, recipientKey, out, apu, apv)
+	default:
+		return nil, fmt.Errorf(
+			"%w: %s expects *ecdsa.PublicKey or *ecdh.PublicKey, got %T", ErrInvalidKey, header.Alg, key,
+		)
+	}
+}
+
+// decryptKey runs the key management algorithm on the recipient side, and returns the CEK.
+func decryptKey(header *Header, encryptedKey []byte, key any) ([]byte, error) {
+	preset, err := contentPreset(header.Enc)
+	if err != nil {
+		return nil, err
+	}
+
+	switch header.Alg {
+	case jwa.DIR:
+		cek, ok := key.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s expects []byte, got %T", ErrInvalidKey, header.Alg, key)
+		}
+
+		if len(encryptedKey) > 0 {
+			return nil, fmt.Errorf("%w: %s expects an empty encrypted key", ErrMalformedToken, header.Alg)
+		}
+
+		return cek, nil
+	case jwa.ECDHES:
+		if len(encryptedKey) > 0 {
+			return nil, fmt.Errorf("%w: %s expects an empty encrypted key", ErrMalformedToken, header.Alg)
+		}
+
+		return deriveKey(header, key, ecdhDirectAlgs[header.Enc])
+	case jwa.RSA15:
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s expects *rsa.PrivateKey, got %T", ErrInvalidKey, header.Alg, key)
+		}
+
+		// To mitigate the attacks described in RFC 3218, the recipient MUST NOT distinguish between format,
+		// padding, and length errors of encrypted keys. A random CEK is used instead of the decrypted one on
+		// failure, so the error only surfaces once the content decryption fails.
+		//
+		// https://datatracker.ietf.org/doc/html/rfc7516#section-11.5
+		randomCEK, err := jwkgen.AES(preset.KeySize)
+		if err != nil {
+			return nil, fmt.Errorf("generate cek: %w", err)
+		}
+
+		cek, err := keyenc.DecryptRSAESPKCS1V15(rsaKey, encryptedKey) //nolint:staticcheck
+		if err != nil || len(cek) != int(preset.KeySize) {
+			return randomCEK, nil //nolint:nilerr
+		}
+
+		return cek, nil
+	case jwa.RSAOAEP, jwa.RSAOAEP256:
+		// Any decrypter backed by an RSA key is accepted, so the private key can live in an HSM or a KMS.
+		rsaKey, ok := key.(crypto.Decrypter)
+		if !ok {
+			return nil, fmt.Errorf(
+				"%w: %s expects *rsa.PrivateKey or crypto.Decrypter, got %T", ErrInvalidKey, header.Alg, key,
+			)
+		}
+
+		if _, ok = rsaKey.Public().(*rsa.PublicKey); !ok {
+			return nil, fmt.Errorf("%w: %s expects an RSA key, got %T", ErrInvalidKey, header.Alg, rsaKey.Public())
+		}
+
+		if header.Alg == jwa.RSAOAEP {
+			return keyenc.DecryptRSAESOAEP(rsaKey, crypto.SHA1, encryptedKey)
+		}
+
+		return keyenc.DecryptRSAESOAEP(rsaKey, crypto.SHA256, encryptedKey)
+	case jwa.A128KW, jwa.A192KW, jwa.A256KW:
+		kek, ok := key.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s expects []byte, got %T", ErrInvalidKey, header.Alg, key)
+		}
+
+		if len(kek) != kwKeySizes[header.Alg] {
+			return nil, fmt.Errorf("%w: %s expects a %d bytes key", ErrInvalidKey, header.Alg, kwKeySizes[header.Alg])
+		}
+
+		return keywrap.UnwrapAES(kek, encryptedKey)
+	case jwa.A128GCMKW, jwa.A192GCMKW, jwa.A256GCMKW:
+		kek, ok := key.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s expects []byte, got %T", ErrInvalidKey, header.Alg, key)
+		}
+
+		return keywrap.UnwrapAESGCM(header.Alg, kek, encryptedKey, &header.AESGCMKeyEncPayload)
+	case jwa.ECDHESA128KW, jwa.ECDHESA192KW, jwa.ECDHESA256KW:
+		kek, err := deriveKey(header, key, ecdhKWAlgs[header.Alg])
+		if err != nil {
+			return nil, err
+		}
+
+		return keywrap.UnwrapAES(kek, encryptedKey)
+	case jwa.PBES2HS256A128KW, jwa.PBES2HS384A192KW, jwa.PBES2HS512A256KW:
+		password, ok := key.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s expects []byte, got %T", ErrInvalidKey, header.Alg, key)
+		}
+
+		if header.P2C <= 0 || header.P2C > PBES2MaxCount {
+			return nil, fmt.Errorf("%w: p2c must be between 1 and %d", ErrMalformedToken, PBES2MaxCount)
+		}
+
+		saltInput, err := base64.RawURLEncoding.DecodeString(header.P2S)
+		if err != nil {
+			return nil, fmt.Errorf("%w: decode p2s: %w", ErrMalformedToken, err)
+		}
+
+		if len(saltInput) < pbes2MinSaltSize {
+			return nil, fmt.Errorf("%w: p2s must be at least %d bytes", ErrMalformedToken, pbes2MinSaltSize)
+		}
+
+		kek, err := keyenc.DerivePBES2(pbes2Hashes[header.Alg], pbes2Salt(header.Alg, saltInput), password, header.P2C)
+		if err != nil {
+			return nil, fmt.Errorf("derive kek: %w", err)
+		}
+
+		return keywrap.UnwrapAES(kek, encryptedKey)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlg, header.Alg)
+	}
+}
+
+// deriveKey derives a key from the recipient private key, and the ephemeral public key from the header.
+func deriveKey(header *Header, key any, out keyagr.Alg) ([]byte, error) {
+	if header.EPK == nil {
+		return nil, fmt.Errorf("%w: missing epk header", ErrMalformedToken)
+	}
+
+	apu, apv, err := decodeAgreementInfo(header)
+	if err != nil {
+		return nil, err
+	}
+
+	ephemeralKey, err := jwejson.DecodeEPK(header.EPK)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	switch recipientKey := key.(type) {
+	case *ecdsa.PrivateKey:
+		ephemeralPubKey, ok := ephemeralKey.(*ecdsa.PublicKey)
+		if !ok || ephemeralPubKey.Curve != recipientKey.Curve {
+			return nil, fmt.Errorf("%w: epk does not match the recipient key", ErrInvalidKey)
+		}
+
+		return keyagr.DeriveECDHES(recipientKey, ephemeralPubKey, out, apu, apv)
+	case *ecdh.PrivateKey:
+		ephemeralPubKey, ok := ephemeralKey.(*ecdh.PublicKey)
+		if !ok || recipientKey.Curve() != ecdh.X25519() {
+			return nil, fmt.Errorf("%w: epk does not match the recipient key", ErrInvalidKey)
+		}
+
+		return keyagr.DeriveECDHED(recipientKey, ephemeralPubKey, out, apu, apv)
+	default:
+		return nil, fmt.Errorf(
+			"%w: %s expects *ecdsa.PrivateKey or *ecdh.PrivateKey, got %T", ErrInvalidKey, header.Alg, key,
+		)
+	}
+}
+
+func decodeAgreementInfo(header *Header) ([]byte, []byte, error) {
+	apu, err := base64.RawURLEncoding.DecodeString(header.APU)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: decode apu: %w", ErrMalformedToken, err)
+	}
+
+	apv, err := base64.RawURLEncoding.DecodeString(header.APV)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: decode apv: %w", ErrMalformedToken, err)
+	}
+
+	return apu, apv, nil
+}
+
+// pbes2Salt computes the salt used by PBES2 from the "p2s" salt input.
+//
+// https://datatracker.ietf.org/doc/html/rfc7518#section-4.8.1.1
+//
+// The salt value used is (UTF8(Alg) || 0x00 || Salt Input), where Alg is the "alg" (algorithm) Header
+// Parameter value.
+func pbes2Salt(alg jwa.Alg, saltInput []byte) []byte {
+	salt := make([]byte, 0, len(alg)+1+len(saltInput))
+	salt = append(salt, alg...)
+	salt = append(salt, 0)
+
+	return append(salt, saltInput...)
+}
@@ -77,19 +77,19 @@ var (
 var (
 	// AlgA128KW is the algorithm used for key agreement with key wrapping with ECDH-ES+A128KW.
 	AlgA128KW = Alg{
-		ID:   string(jwa.A128KW),
+		ID:   string(jwa.ECDHESA128KW),
 		Size: int(jwkgen.AESKeySize128),
 		Type: AlgTypeKeyWrap,
 	}
 	// AlgA192KW is the algorithm used for key agreement with key wrapping with ECDH-ES+A192KW.
 	AlgA192KW = Alg{
-		ID:   string(jwa.A192KW),
+		ID:   string(jwa.ECDHESA192KW),
 		Size: int(jwkgen.AESKeySize192),
 		Type: AlgTypeKeyWrap,
 	}
 	// AlgA256KW is the algorithm used for key agreement with key wrapping with ECDH-ES+A256KW.
 	AlgA256KW = Alg{
-		ID:   string(jwa.A256KW),
+		ID:   string(jwa.ECDHESA256KW),
 		Size: int(jwkgen.AESKeySize256),
 		Type: AlgTypeKeyWrap,
 	}
@@ -1,7 +1,10 @@
 package keyagr_test
 
 import (
+	"crypto/ecdsa"
 	"crypto/elliptic"
+	"encoding/base64"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -93,3 +96,81 @@ func TestDeriveECDHES(t *testing.T) {
 		require.NotEqual(t, issuerCEK, recipientCEK)
 	})
 }
+
+// https://datatracker.ietf.org/doc/html/rfc7518#appendix-C
+func TestDeriveECDHESVector(t *testing.T) {
+	decodeInt := func(src string) *big.Int {
+		decoded, err := base64.RawURLEncoding.DecodeString(src)
+		require.NoError(t, err)
+
+		return new(big.Int).SetBytes(decoded)
+	}
+
+	// Alice's ephemeral key.
+	issuerKey := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     decodeInt("gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0"),
+			Y:     decodeInt("SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps"),
+		},
+		D: decodeInt("0_NxaRPUMQoAJt50Gz8YiTr8gRTwyEaCumd-MToTmIo"),
+	}
+
+	// Bob's key.
+	recipientKey := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     decodeInt("weNJy2HscCSM6AEDTDg04biOvhFhyyWvOHQfeF_PxMQ"),
+			Y:     decodeInt("e8lnCO-AlStT-NJVX-crhB7QRYhiix03illJOVAOyck"),
+		},
+		D: decodeInt("VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw"),
+	}
+
+	testCases := []struct {
+		name string
+
+		alg keyagr.Alg
+
+		expect string
+	}{
+		{
+			// Value given by the RFC.
+			name:   "AlgA128GCM",
+			alg:    keyagr.AlgA128GCM,
+			expect: "VqqN6vgjbSBcIijNcacQGg",
+		},
+		// The RFC does not give values for key wrapping. The AlgorithmID of these is the full "alg" value, such as
+		// "ECDH-ES+A128KW".
+		{
+			name:   "AlgA128KW",
+			alg:    keyagr.AlgA128KW,
+			expect: "PPIpxRmqlZFiLBGVFGOyWg",
+		},
+		{
+			name:   "AlgA192KW",
+			alg:    keyagr.AlgA192KW,
+			expect: "DWUs0735lRAx63PAf1WYN_bKTaKsqlSB",
+		},
+		{
+			name:   "AlgA256KW",
+			alg:    keyagr.AlgA256KW,
+			expect: "jPbrGa9q4JbGATtcezUK3KuIZ-Jvwo-npGamnceSVSE",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			issuerCEK, err := keyagr.DeriveECDHES(
+				issuerKey, &recipientKey.PublicKey, testCase.alg, []byte("Alice"), []byte("Bob"),
+			)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expect, base64.RawURLEncoding.EncodeToString(issuerCEK))
+
+			recipientCEK, err := keyagr.DeriveECDHES(
+				recipientKey, &issuerKey.PublicKey, testCase.alg, []byte("Alice"), []byte("Bob"),
+			)
+			require.NoError(t, err)
+			require.Equal(t, issuerCEK, recipientCEK)
+		})
+	}
+}
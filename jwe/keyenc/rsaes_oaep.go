@@ -1,6 +1,7 @@
 package keyenc
 
 import (
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"fmt"
@@ -18,8 +19,18 @@ func EncryptRSAESOAEP(key *rsa.PublicKey, keyHash hash.Hash, cek []byte) ([]byte
 }
 
 // DecryptRSAESOAEP decrypts the CEK using RSAES-OAEP algorithm.
-func DecryptRSAESOAEP(key *rsa.PrivateKey, keyHash hash.Hash, encrypted []byte) ([]byte, error) {
-	decoded, err := rsa.DecryptOAEP(keyHash, rand.Reader, key, encrypted, nil)
+//
+// The key can be any crypto.Decrypter backed by an RSA key, such as a *rsa.PrivateKey or a key stored in a KMS.
+func DecryptRSAESOAEP(key crypto.Decrypter, keyHash crypto.Hash, encrypted []byte) ([]byte, error) {
+	if !keyHash.Available() {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedHash, keyHash)
+	}
+
+	if _, ok := key.Public().(*rsa.PublicKey); !ok {
+		return nil, fmt.Errorf("decrypt RSAES-OAEP: expected an RSA key, got %T", key.Public())
+	}
+
+	decoded, err := key.Decrypt(rand.Reader, encrypted, &rsa.OAEPOptions{Hash: keyHash})
 	if err != nil {
 		return nil, fmt.Errorf("decrypt RSAES-OAEP: %w", err)
 	}
@@ -1,6 +1,7 @@
 package keyenc_test
 
 import (
+	"crypto"
 	"crypto/sha1"
 	"crypto/sha256"
 	"testing"
@@ -23,13 +24,13 @@ func TestRSAESOAEP(t *testing.T) {
 	encrypted, err := keyenc.EncryptRSAESOAEP(&key.PublicKey, sha1.New(), cek)
 	require.NoError(t, err)
 
-	decrypted, err := keyenc.DecryptRSAESOAEP(key, sha1.New(), encrypted)
+	decrypted, err := keyenc.DecryptRSAESOAEP(key, crypto.SHA1, encrypted)
 	require.NoError(t, err)
 
 	require.Equal(t, cek, decrypted)
 
 	t.Run("invalid key", func(t *testing.T) {
-		_, err := keyenc.DecryptRSAESOAEP(key2, sha1.New(), encrypted)
+		_, err := keyenc.DecryptRSAESOAEP(key2, crypto.SHA1, encrypted)
 		require.Error(t, err)
 	})
 }
@@ -46,13 +47,13 @@ func TestRSAESOAEP256(t *testing.T) {
 	encrypted, err := keyenc.EncryptRSAESOAEP(&key.PublicKey, sha256.New(), cek)
 	require.NoError(t, err)
 
-	decrypted, err := keyenc.DecryptRSAESOAEP(key, sha256.New(), encrypted)
+	decrypted, err := keyenc.DecryptRSAESOAEP(key, crypto.SHA256, encrypted)
 	require.NoError(t, err)
 
 	require.Equal(t, cek, decrypted)
 
 	t.Run("invalid key", func(t *testing.T) {
-		_, err := keyenc.DecryptRSAESOAEP(key2, sha256.New(), encrypted)
+		_, err := keyenc.DecryptRSAESOAEP(key2, crypto.SHA256, encrypted)
 		require.Error(t, err)
 	})
 }
@@ -0,0 +1,109 @@
+package keywrap
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+	"github.com/a-novel-kit/jwt-core/jwe/enc"
+	jwejson "github.com/a-novel-kit/jwt-core/jwe/json"
+	jwkcore "github.com/a-novel-kit/jwt-core/jwk"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+)
+
+var (
+	ErrUnsupportedAlg = errors.New("unsupported key wrapping algorithm")
+	ErrInvalidKEK     = errors.New("invalid key encryption key")
+	ErrInvalidParams  = errors.New("invalid key wrapping parameters")
+)
+
+// aesGCMKeySizes maps AES GCM key wrapping algorithms to the size of their key encryption key.
+var aesGCMKeySizes = map[jwa.Alg]jwkgen.AESKeySize{
+	jwa.A128GCMKW: jwkgen.AESKeySize128,
+	jwa.A192GCMKW: jwkgen.AESKeySize192,
+	jwa.A256GCMKW: jwkgen.AESKeySize256,
+}
+
+// WrapAESGCM wraps a Content Encryption Key (CEK) with a Key Encryption Key (KEK), using AES in Galois/Counter Mode.
+// It returns the wrapped key (JWRK), along with the "iv" and "tag" header parameters required to unwrap it.
+//
+// https://datatracker.ietf.org/doc/html/rfc7518#section-4.7
+//
+// Use of an Initialization Vector (IV) of size 96 bits is REQUIRED with
+// this algorithm. The IV is represented in base64url-encoded form as
+// the "iv" (initialization vector) Header Parameter value.
+//
+// The Additional Authenticated Data value used is the empty octet
+// string.
+//
+// The requested size of the Authentication Tag output MUST be 128 bits,
+// regardless of the key size.
+func WrapAESGCM(alg jwa.Alg, kek, cek []byte) ([]byte, *jwejson.AESGCMKeyEncPayload, error) {
+	if err := checkAESGCMKEK(alg, kek); err != nil {
+		return nil, nil, err
+	}
+
+	iv, err := jwkgen.IV(jwkgen.IVSize96)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate iv: %w", err)
+	}
+
+	encrypted, err := enc.EncryptAESGCM(cek, nil, &jwkcore.AESKeySet{CEK: kek, IV: iv})
+	if err != nil {
+		return nil, nil, fmt.Errorf("encrypt key: %w", err)
+	}
+
+	return encrypted.E, &jwejson.AESGCMKeyEncPayload{
+		IV:  base64.RawURLEncoding.EncodeToString(iv),
+		Tag: base64.RawURLEncoding.EncodeToString(encrypted.T),
+	}, nil
+}
+
+// UnwrapAESGCM unwraps a JWE Wrapped Key (JWRK) with a Key Encryption Key (KEK), using AES in Galois/Counter Mode.
+// The "iv" and "tag" header parameters are the ones returned by WrapAESGCM.
+//
+// https://datatracker.ietf.org/doc/html/rfc7518#section-4.7
+func UnwrapAESGCM(alg jwa.Alg, kek, jwrk []byte, params *jwejson.AESGCMKeyEncPayload) ([]byte, error) {
+	if err := checkAESGCMKEK(alg, kek); err != nil {
+		return nil, err
+	}
+
+	if params == nil {
+		return nil, fmt.Errorf("%w: missing iv and tag", ErrInvalidParams)
+	}
+
+	iv, err := base64.RawURLEncoding.DecodeString(params.IV)
+	if err != nil {
+		return nil, fmt.Errorf("%w: decode iv: %w", ErrInvalidParams, err)
+	}
+
+	if len(iv) != int(jwkgen.IVSize96) {
+		return nil, fmt.Errorf("%w: iv must be %d bytes", ErrInvalidParams, jwkgen.IVSize96)
+	}
+
+	tag, err := base64.RawURLEncoding.DecodeString(params.Tag)
+	if err != nil {
+		return nil, fmt.Errorf("%w: decode tag: %w", ErrInvalidParams, err)
+	}
+
+	cek, err := enc.DecryptAESGCM(&enc.AESPayload{E: jwrk, T: tag}, nil, &jwkcore.AESKeySet{CEK: kek, IV: iv})
+	if err != nil {
+		return nil, fmt.Errorf("decrypt key: %w", err)
+	}
+
+	return cek, nil
+}
+
+func checkAESGCMKEK(alg jwa.Alg, kek []byte) error {
+	size, ok := aesGCMKeySizes[alg]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnsupportedAlg, alg)
+	}
+
+	if len(kek) != int(size) {
+		return fmt.Errorf("%w: %s expects a %d bytes key, got %d", ErrInvalidKEK, alg, size, len(kek))
+	}
+
+	return nil
+}
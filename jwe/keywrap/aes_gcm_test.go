@@ -0,0 +1,98 @@
+package keywrap_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwejson "github.com/a-novel-kit/jwt-core/jwe/json"
+	"github.com/a-novel-kit/jwt-core/jwe/keywrap"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+)
+
+func TestAESGCM(t *testing.T) {
+	cek, err := jwkgen.AES(jwkgen.AESKeySize512)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		alg     jwa.Alg
+		kekSize jwkgen.AESKeySize
+	}{
+		{
+			name:    "A128GCMKW",
+			alg:     jwa.A128GCMKW,
+			kekSize: jwkgen.AESKeySize128,
+		},
+		{
+			name:    "A192GCMKW",
+			alg:     jwa.A192GCMKW,
+			kekSize: jwkgen.AESKeySize192,
+		},
+		{
+			name:    "A256GCMKW",
+			alg:     jwa.A256GCMKW,
+			kekSize: jwkgen.AESKeySize256,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			kek, err := jwkgen.AES(testCase.kekSize)
+			require.NoError(t, err)
+
+			jwrk, params, err := keywrap.WrapAESGCM(testCase.alg, kek, cek)
+			require.NoError(t, err)
+			require.NotEmpty(t, params.IV)
+			require.NotEmpty(t, params.Tag)
+
+			unwrapped, err := keywrap.UnwrapAESGCM(testCase.alg, kek, jwrk, params)
+			require.NoError(t, err)
+			require.Equal(t, cek, unwrapped)
+
+			otherKEK, err := jwkgen.AES(testCase.kekSize)
+			require.NoError(t, err)
+
+			_, err = keywrap.UnwrapAESGCM(testCase.alg, otherKEK, jwrk, params)
+			require.Error(t, err)
+		})
+	}
+
+	t.Run("invalid KEK size", func(t *testing.T) {
+		kek, err := jwkgen.AES(jwkgen.AESKeySize128)
+		require.NoError(t, err)
+
+		_, _, err = keywrap.WrapAESGCM(jwa.A256GCMKW, kek, cek)
+		require.ErrorIs(t, err, keywrap.ErrInvalidKEK)
+
+		_, err = keywrap.UnwrapAESGCM(jwa.A256GCMKW, kek, cek, &jwejson.AESGCMKeyEncPayload{})
+		require.ErrorIs(t, err, keywrap.ErrInvalidKEK)
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		kek, err := jwkgen.AES(jwkgen.AESKeySize128)
+		require.NoError(t, err)
+
+		_, _, err = keywrap.WrapAESGCM(jwa.A128KW, kek, cek)
+		require.ErrorIs(t, err, keywrap.ErrUnsupportedAlg)
+	})
+
+	t.Run("invalid iv", func(t *testing.T) {
+		kek, err := jwkgen.AES(jwkgen.AESKeySize128)
+		require.NoError(t, err)
+
+		jwrk, params, err := keywrap.WrapAESGCM(jwa.A128GCMKW, kek, cek)
+		require.NoError(t, err)
+
+		_, err = keywrap.UnwrapAESGCM(jwa.A128GCMKW, kek, jwrk, &jwejson.AESGCMKeyEncPayload{
+			IV:  "AAAA",
+			Tag: params.Tag,
+		})
+		require.ErrorIs(t, err, keywrap.ErrInvalidParams)
+
+		_, err = keywrap.UnwrapAESGCM(jwa.A128GCMKW, kek, jwrk, nil)
+		require.ErrorIs(t, err, keywrap.ErrInvalidParams)
+	})
+}
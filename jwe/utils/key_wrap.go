@@ -58,6 +58,11 @@ func KeyUnwrap(block cipher.Block, ciphertext []byte) ([]byte, error) {
 		return nil, errors.New("key wrap input must be 8 byte blocks")
 	}
 
+	// The wrapped key holds at least the integrity check block, and one block of key data.
+	if len(ciphertext) < 16 {
+		return nil, errors.New("key wrap input is too short")
+	}
+
 	n := (len(ciphertext) / 8) - 1
 	r := make([][]byte, n)
 
@@ -65,6 +65,10 @@ func TestAesKeyWrapInvalid(t *testing.T) {
 	_, err = jweutils.KeyUnwrap(block, input1)
 	require.Error(t, err, "key unwrap failed to detect truncated input")
 
+	// Invalid unwrap input (empty)
+	_, err = jweutils.KeyUnwrap(block, nil)
+	require.Error(t, err, "key unwrap failed to detect empty input")
+
 	// Invalid wrap input (not multiple of 8)
 	input2, _ := hex.DecodeString("0123456789ABCD")
 
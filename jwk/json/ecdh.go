@@ -3,6 +3,7 @@ package jwkjson
 import (
 	"crypto/ecdh"
 	"encoding/base64"
+	"errors"
 	"fmt"
 )
 
@@ -27,6 +28,8 @@ type ECDHPayload struct {
 	D string `json:"d,omitempty"`
 }
 
+var ErrInvalidECDHKey = errors.New("invalid ECDH key")
+
 // DecodeECDH decodes the ECDH-ES key from a JWK format.
 func DecodeECDH(src *ECDHPayload) (*ecdh.PrivateKey, *ecdh.PublicKey, error) {
 	if src.Crv != "X25519" {
@@ -57,6 +60,10 @@ func DecodeECDH(src *ECDHPayload) (*ecdh.PrivateKey, *ecdh.PublicKey, error) {
 		return nil, nil, fmt.Errorf("create ecdh private key: %w", err)
 	}
 
+	if !ecdhPrivKey.PublicKey().Equal(ecdhPubKey) {
+		return nil, nil, fmt.Errorf("%w: private key does not match the public key", ErrInvalidECDHKey)
+	}
+
 	return ecdhPrivKey, ecdhPubKey, nil
 }
 
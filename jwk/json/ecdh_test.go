@@ -45,3 +45,26 @@ func TestEncodeDecodeECFHPublic(t *testing.T) {
 		require.True(t, key1.PublicKey().Equal(decodedPub))
 	})
 }
+
+func TestDecodeECDHValidation(t *testing.T) {
+	key, err := jwkgen.X25519()
+	require.NoError(t, err)
+
+	otherKey, err := jwkgen.X25519()
+	require.NoError(t, err)
+
+	encoded, err := jwkjson.EncodeECDH(key)
+	require.NoError(t, err)
+
+	encodedOther, err := jwkjson.EncodeECDH(otherKey)
+	require.NoError(t, err)
+
+	decodedPriv, decodedPub, err := jwkjson.DecodeECDH(&jwkjson.ECDHPayload{
+		Crv: encoded.Crv,
+		X:   encoded.X,
+		D:   encodedOther.D,
+	})
+	require.ErrorIs(t, err, jwkjson.ErrInvalidECDHKey)
+	require.Nil(t, decodedPriv)
+	require.Nil(t, decodedPub)
+}
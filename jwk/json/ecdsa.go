@@ -1,6 +1,7 @@
 package jwkjson
 
 import (
+	"crypto/ecdh"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"encoding/base64"
@@ -73,33 +74,64 @@ type ECPayload struct {
 	D string `json:"d,omitempty"`
 }
 
-var ErrUnsupportedCurve = errors.New("unsupported curve")
+var (
+	ErrUnsupportedCurve = errors.New("unsupported curve")
+	ErrInvalidECKey     = errors.New("invalid ECDSA key")
+)
 
 // DecodeEC takes the representation of a ECPayload and computes the key it contains.
+//
+// The key is validated on import: the public point must be on the curve, and must not be the point at infinity.
+// If present, the private key must be in the range [1, n-1], and match the public point. Without those checks,
+// a crafted key could leak the private key of the other party during key agreement (invalid curve attack).
 func DecodeEC(src *ECPayload) (*ecdsa.PrivateKey, *ecdsa.PublicKey, error) {
-	var curve elliptic.Curve
+	var (
+		curve     elliptic.Curve
+		ecdhCurve ecdh.Curve
+	)
 
 	switch src.Crv {
 	case "P-256":
-		curve = elliptic.P256()
+		curve, ecdhCurve = elliptic.P256(), ecdh.P256()
 	case "P-384":
-		curve = elliptic.P384()
+		curve, ecdhCurve = elliptic.P384(), ecdh.P384()
 	case "P-521":
-		curve = elliptic.P521()
+		curve, ecdhCurve = elliptic.P521(), ecdh.P521()
 	default:
 		return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedCurve, src.Crv)
 	}
 
+	coordinateSize := ecCoordinateSize(curve)
+
 	x, err := base64.RawURLEncoding.DecodeString(src.X)
 	if err != nil {
 		return nil, nil, fmt.Errorf("decode x: %w", err)
 	}
 
+	if len(x) != coordinateSize {
+		return nil, nil, fmt.Errorf("%w: x must be %d bytes long, got %d", ErrInvalidECKey, coordinateSize, len(x))
+	}
+
 	y, err := base64.RawURLEncoding.DecodeString(src.Y)
 	if err != nil {
 		return nil, nil, fmt.Errorf("decode y: %w", err)
 	}
 
+	if len(y) != coordinateSize {
+		return nil, nil, fmt.Errorf("%w: y must be %d bytes long, got %d", ErrInvalidECKey, coordinateSize, len(y))
+	}
+
+	// The uncompressed form of the point is parsed by crypto/ecdh, which rejects points that are not on the curve,
+	// as well as the point at infinity.
+	//
+	// https://www.secg.org/sec1-v2.pdf#section.2.3.3
+	point := append(append([]byte{4}, x...), y...)
+
+	ecdhPub, err := ecdhCurve.NewPublicKey(point)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidECKey, err)
+	}
+
 	keyPub := &ecdsa.PublicKey{
 		Curve: curve,
 		X:     new(big.Int).SetBytes(x),
@@ -115,6 +147,19 @@ func DecodeEC(src *ECPayload) (*ecdsa.PrivateKey, *ecdsa.PublicKey, error) {
 		return nil, nil, fmt.Errorf("decode d: %w", err)
 	}
 
+	if privateKeySize := ecPrivateKeySize(curve); len(d) != privateKeySize {
+		return nil, nil, fmt.Errorf("%w: d must be %d bytes long, got %d", ErrInvalidECKey, privateKeySize, len(d))
+	}
+
+	ecdhPriv, err := ecdhCurve.NewPrivateKey(d)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidECKey, err)
+	}
+
+	if !ecdhPriv.PublicKey().Equal(ecdhPub) {
+		return nil, nil, fmt.Errorf("%w: private key does not match the public key", ErrInvalidECKey)
+	}
+
 	keyPriv := &ecdsa.PrivateKey{
 		PublicKey: *keyPub,
 		D:         new(big.Int).SetBytes(d),
@@ -123,25 +168,53 @@ func DecodeEC(src *ECPayload) (*ecdsa.PrivateKey, *ecdsa.PublicKey, error) {
 	return keyPriv, keyPub, nil
 }
 
-// EncodeEC takes a key and create a ECPayload representation of it.
+// EncodeEC takes a key and create a ECPayload representation of it. Coordinates and private key are padded to the
+// full size required by the curve.
 func EncodeEC[Key *ecdsa.PublicKey | *ecdsa.PrivateKey](key Key) (*ECPayload, error) {
-	payload := new(ECPayload)
-
-	pubKey, ok := any(key).(*ecdsa.PublicKey)
-	if ok {
-		payload.Crv = pubKey.Curve.Params().Name
-		payload.X = base64.RawURLEncoding.EncodeToString(pubKey.X.Bytes())
-		payload.Y = base64.RawURLEncoding.EncodeToString(pubKey.Y.Bytes())
+	privKey, isPrivate := any(key).(*ecdsa.PrivateKey)
 
-		return payload, nil
+	var pubKey *ecdsa.PublicKey
+	if isPrivate {
+		pubKey = &privKey.PublicKey
+	} else {
+		pubKey = any(key).(*ecdsa.PublicKey)
 	}
 
-	privKey := any(key).(*ecdsa.PrivateKey)
+	coordinateSize := ecCoordinateSize(pubKey.Curve)
 
-	payload.Crv = privKey.PublicKey.Curve.Params().Name
-	payload.X = base64.RawURLEncoding.EncodeToString(privKey.X.Bytes())
-	payload.Y = base64.RawURLEncoding.EncodeToString(privKey.Y.Bytes())
-	payload.D = base64.RawURLEncoding.EncodeToString(privKey.D.Bytes())
+	payload := &ECPayload{
+		Crv: pubKey.Curve.Params().Name,
+		X:   base64.RawURLEncoding.EncodeToString(pubKey.X.FillBytes(make([]byte, coordinateSize))),
+		Y:   base64.RawURLEncoding.EncodeToString(pubKey.Y.FillBytes(make([]byte, coordinateSize))),
+	}
+
+	if isPrivate {
+		privateKeySize := ecPrivateKeySize(pubKey.Curve)
+		payload.D = base64.RawURLEncoding.EncodeToString(privKey.D.FillBytes(make([]byte, privateKeySize)))
+	}
 
 	return payload, nil
 }
+
+// ecCoordinateSize returns the size, in bytes, of a coordinate on the curve.
+//
+// https://datatracker.ietf.org/doc/html/rfc7518#section-6.2.1.2
+//
+// The length of this octet string MUST
+// be the full size of a coordinate for the curve specified in the "crv"
+// parameter. For example, if the value of "crv" is "P-521", the octet
+// string must be 66 octets long.
+func ecCoordinateSize(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8
+}
+
+// ecPrivateKeySize returns the size, in bytes, of a private key on the curve.
+//
+// https://datatracker.ietf.org/doc/html/rfc7518#section-6.2.2.1
+//
+// The length of this octet string
+// MUST be ceiling(log-base-2(n)/8) octets (where n is the order of the
+// curve).
+func ecPrivateKeySize(curve elliptic.Curve) int {
+	return (curve.Params().N.BitLen() + 7) / 8
+}
@@ -1,7 +1,10 @@
 package jwkjson_test
 
 import (
+	"crypto/ecdsa"
 	"crypto/elliptic"
+	"encoding/base64"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -46,3 +49,160 @@ func TestEncodeDecodeECPublic(t *testing.T) {
 		require.True(t, key1.PublicKey.Equal(decodedPub))
 	})
 }
+
+func TestEncodeECPadding(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		curve elliptic.Curve
+
+		expectCoordinateSize int
+	}{
+		{
+			name:                 "P-256",
+			curve:                elliptic.P256(),
+			expectCoordinateSize: 32,
+		},
+		{
+			name:                 "P-384",
+			curve:                elliptic.P384(),
+			expectCoordinateSize: 48,
+		},
+		{
+			name:                 "P-521",
+			curve:                elliptic.P521(),
+			expectCoordinateSize: 66,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Small values have leading zero bytes once padded.
+			key := &ecdsa.PrivateKey{
+				PublicKey: ecdsa.PublicKey{Curve: testCase.curve, X: big.NewInt(1), Y: big.NewInt(2)},
+				D:         big.NewInt(3),
+			}
+
+			encoded, err := jwkjson.EncodeEC(key)
+			require.NoError(t, err)
+
+			for _, value := range []string{encoded.X, encoded.Y, encoded.D} {
+				decoded, err := base64.RawURLEncoding.DecodeString(value)
+				require.NoError(t, err)
+				require.Len(t, decoded, testCase.expectCoordinateSize)
+			}
+
+			encodedPub, err := jwkjson.EncodeEC(&key.PublicKey)
+			require.NoError(t, err)
+			require.Equal(t, encoded.X, encodedPub.X)
+			require.Equal(t, encoded.Y, encodedPub.Y)
+			require.Empty(t, encodedPub.D)
+		})
+	}
+}
+
+func TestDecodeECLength(t *testing.T) {
+	key, err := jwkgen.EC(elliptic.P256())
+	require.NoError(t, err)
+
+	encoded, err := jwkjson.EncodeEC(key)
+	require.NoError(t, err)
+
+	// Removes the first byte of an encoded value.
+	truncate := func(value string) string {
+		decoded, err := base64.RawURLEncoding.DecodeString(value)
+		require.NoError(t, err)
+
+		return base64.RawURLEncoding.EncodeToString(decoded[1:])
+	}
+
+	testCases := []struct {
+		name string
+
+		payload *jwkjson.ECPayload
+	}{
+		{
+			name:    "short x",
+			payload: &jwkjson.ECPayload{Crv: encoded.Crv, X: truncate(encoded.X), Y: encoded.Y},
+		},
+		{
+			name:    "short y",
+			payload: &jwkjson.ECPayload{Crv: encoded.Crv, X: encoded.X, Y: truncate(encoded.Y)},
+		},
+		{
+			name:    "short d",
+			payload: &jwkjson.ECPayload{Crv: encoded.Crv, X: encoded.X, Y: encoded.Y, D: truncate(encoded.D)},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			decodedPriv, decodedPub, err := jwkjson.DecodeEC(testCase.payload)
+			require.ErrorIs(t, err, jwkjson.ErrInvalidECKey)
+			require.Nil(t, decodedPriv)
+			require.Nil(t, decodedPub)
+		})
+	}
+}
+
+func TestDecodeECValidation(t *testing.T) {
+	key, err := jwkgen.EC(elliptic.P256())
+	require.NoError(t, err)
+
+	otherKey, err := jwkgen.EC(elliptic.P256())
+	require.NoError(t, err)
+
+	encoded, err := jwkjson.EncodeEC(key)
+	require.NoError(t, err)
+
+	encodedOther, err := jwkjson.EncodeEC(otherKey)
+	require.NoError(t, err)
+
+	// Moves the point off the curve.
+	offCurveY := new(big.Int).Add(key.Y, big.NewInt(1)).FillBytes(make([]byte, 32))
+
+	testCases := []struct {
+		name string
+
+		payload *jwkjson.ECPayload
+	}{
+		{
+			name: "point not on curve",
+			payload: &jwkjson.ECPayload{
+				Crv: encoded.Crv,
+				X:   encoded.X,
+				Y:   base64.RawURLEncoding.EncodeToString(offCurveY),
+			},
+		},
+		{
+			name: "point at infinity",
+			payload: &jwkjson.ECPayload{
+				Crv: encoded.Crv,
+				X:   base64.RawURLEncoding.EncodeToString(make([]byte, 32)),
+				Y:   base64.RawURLEncoding.EncodeToString(make([]byte, 32)),
+			},
+		},
+		{
+			name:    "private key mismatch",
+			payload: &jwkjson.ECPayload{Crv: encoded.Crv, X: encoded.X, Y: encoded.Y, D: encodedOther.D},
+		},
+		{
+			name: "zero private key",
+			payload: &jwkjson.ECPayload{
+				Crv: encoded.Crv,
+				X:   encoded.X,
+				Y:   encoded.Y,
+				D:   base64.RawURLEncoding.EncodeToString(make([]byte, 32)),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			decodedPriv, decodedPub, err := jwkjson.DecodeEC(testCase.payload)
+			require.ErrorIs(t, err, jwkjson.ErrInvalidECKey)
+			require.Nil(t, decodedPriv)
+			require.Nil(t, decodedPub)
+		})
+	}
+}
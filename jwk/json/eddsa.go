@@ -31,6 +31,12 @@ type EDPayload struct {
 var ErrInvalidEDKey = errors.New("invalid EdDSA key")
 
 // DecodeED decodes the EdDSA key from a JWK format.
+//
+// The private key is expected to be the 32 bytes seed, as described by RFC 8037. The 64 bytes form, made of the
+// seed and the public key, is also accepted. In both cases, the public key derived from the seed must match the
+// "x" parameter.
+//
+// https://datatracker.ietf.org/doc/html/rfc8037#section-2
 func DecodeED(src *EDPayload) (ed25519.PrivateKey, ed25519.PublicKey, error) {
 	if src.Crv != "Ed25519" {
 		return nil, nil, ErrUnsupportedCurve
@@ -56,16 +62,28 @@ func DecodeED(src *EDPayload) (ed25519.PrivateKey, ed25519.PublicKey, error) {
 		return nil, nil, fmt.Errorf("decode eddsa private key: %w", err)
 	}
 
-	if len(privateKey) != ed25519.PrivateKeySize {
+	var edPrivKey ed25519.PrivateKey
+
+	switch len(privateKey) {
+	case ed25519.SeedSize:
+		edPrivKey = ed25519.NewKeyFromSeed(privateKey)
+	case ed25519.PrivateKeySize:
+		edPrivKey = ed25519.NewKeyFromSeed(privateKey[:ed25519.SeedSize])
+		if !edPrivKey.Equal(ed25519.PrivateKey(privateKey)) {
+			return nil, nil, fmt.Errorf("%w: private key does not match its seed", ErrInvalidEDKey)
+		}
+	default:
 		return nil, nil, fmt.Errorf("%w: invalid private key size", ErrInvalidEDKey)
 	}
 
-	edPrivKey := ed25519.PrivateKey(privateKey)
+	if !edPubKey.Equal(edPrivKey.Public()) {
+		return nil, nil, fmt.Errorf("%w: private key does not match the public key", ErrInvalidEDKey)
+	}
 
 	return edPrivKey, edPubKey, nil
 }
 
-// EncodeED returns the JWK representation of an EdDSA key.
+// EncodeED returns the JWK representation of an EdDSA key. The private key is encoded as its seed.
 func EncodeED[Key ed25519.PublicKey | ed25519.PrivateKey](key Key) *EDPayload {
 	pubKey, ok := any(key).(ed25519.PublicKey)
 	if ok {
@@ -79,7 +97,7 @@ func EncodeED[Key ed25519.PublicKey | ed25519.PrivateKey](key Key) *EDPayload {
 	privKey := any(key).(ed25519.PrivateKey)
 
 	encodedPub := base64.RawURLEncoding.EncodeToString(privKey.Public().(ed25519.PublicKey))
-	encodedPriv := base64.RawURLEncoding.EncodeToString(privKey)
+	encodedPriv := base64.RawURLEncoding.EncodeToString(privKey.Seed())
 
 	return &EDPayload{
 		Crv: "Ed25519",
@@ -1,6 +1,8 @@
 package jwkjson_test
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -44,3 +46,71 @@ func TestEncodeDecodeEDPublic(t *testing.T) {
 		require.Equal(t, pubKey, decodedPub)
 	})
 }
+
+func TestDecodeEDValidation(t *testing.T) {
+	privKey, _, err := jwkgen.ED25519()
+	require.NoError(t, err)
+
+	otherPrivKey, _, err := jwkgen.ED25519()
+	require.NoError(t, err)
+
+	encoded := jwkjson.EncodeED(privKey)
+
+	// Private key made of the seed of a key, and the public key of another.
+	mixedPrivKey := append(append([]byte{}, privKey.Seed()...), otherPrivKey.Public().(ed25519.PublicKey)...)
+
+	testCases := []struct {
+		name string
+
+		payload *jwkjson.EDPayload
+
+		expectErr error
+	}{
+		{
+			// https://datatracker.ietf.org/doc/html/rfc8037#appendix-A.1
+			name: "seed",
+			payload: &jwkjson.EDPayload{
+				Crv: "Ed25519",
+				X:   "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",
+				D:   "nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",
+			},
+		},
+		{
+			name: "full private key",
+			payload: &jwkjson.EDPayload{
+				Crv: "Ed25519",
+				X:   encoded.X,
+				D:   base64.RawURLEncoding.EncodeToString(privKey),
+			},
+		},
+		{
+			name: "seed mismatch",
+			payload: &jwkjson.EDPayload{
+				Crv: "Ed25519",
+				X:   encoded.X,
+				D:   jwkjson.EncodeED(otherPrivKey).D,
+			},
+			expectErr: jwkjson.ErrInvalidEDKey,
+		},
+		{
+			name: "inconsistent private key",
+			payload: &jwkjson.EDPayload{
+				Crv: "Ed25519",
+				X:   encoded.X,
+				D:   base64.RawURLEncoding.EncodeToString(mixedPrivKey),
+			},
+			expectErr: jwkjson.ErrInvalidEDKey,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			decodedPriv, decodedPub, err := jwkjson.DecodeED(testCase.payload)
+			require.ErrorIs(t, err, testCase.expectErr)
+
+			if testCase.expectErr == nil {
+				require.Equal(t, decodedPub, decodedPriv.Public())
+			}
+		})
+	}
+}
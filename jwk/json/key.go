@@ -0,0 +1,253 @@
+package jwkjson
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+)
+
+var (
+	ErrUnsupportedKeyType = errors.New("unsupported key type")
+	ErrKeyTypeMismatch    = errors.New("key type does not match key material")
+)
+
+// Key is a JSON Web Key, that carries both the metadata of the key (jwa.JWK) and its material.
+//
+// https://datatracker.ietf.org/doc/html/rfc7517#section-4
+//
+// When decoded, the type of the key is selected from the "kty" parameter, and from the "crv" parameter for
+// Octet Key Pairs. The Key member then holds one of the following values:
+//
+//   - oct: []byte
+//   - RSA: *rsa.PublicKey, *rsa.PrivateKey
+//   - EC: *ecdsa.PublicKey, *ecdsa.PrivateKey
+//   - OKP, with "crv" Ed25519: ed25519.PublicKey, ed25519.PrivateKey
+//   - OKP, with "crv" X25519: *ecdh.PublicKey, *ecdh.PrivateKey
+//
+// The same values are accepted when encoding. If the "kty" parameter is empty, it is set from the key material.
+type Key struct {
+	jwa.JWK
+
+	// Key is the cryptographic key.
+	Key any
+}
+
+// okpPayload reads the curve of an Octet Key Pair, before its material is decoded.
+//
+// https://datatracker.ietf.org/doc/html/rfc8037#section-2
+type okpPayload struct {
+	Crv string `json:"crv"`
+}
+
+// Public returns the public counterpart of the key. Symmetric keys are returned as is.
+func (key *Key) Public() any {
+	switch typed := key.Key.(type) {
+	case *rsa.PrivateKey:
+		return &typed.PublicKey
+	case *ecdsa.PrivateKey:
+		return &typed.PublicKey
+	case ed25519.PrivateKey:
+		return typed.Public()
+	case *ecdh.PrivateKey:
+		return typed.PublicKey()
+	default:
+		return key.Key
+	}
+}
+
+// IsPrivate returns true if the key holds private material. Symmetric keys are always private.
+func (key *Key) IsPrivate() bool {
+	switch key.Key.(type) {
+	case []byte, *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey, *ecdh.PrivateKey:
+		return true
+	default:
+		return false
+	}
+}
+
+// MarshalJSON serializes the key, merging its metadata and its material into a single object.
+func (key Key) MarshalJSON() ([]byte, error) {
+	kty, payload, err := encodeKey(key.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key.KTY {
+	case "":
+		key.KTY = kty
+	case kty:
+	default:
+		return nil, fmt.Errorf("%w: kty is %q, key material is %q", ErrKeyTypeMismatch, key.KTY, kty)
+	}
+
+	serialized, err := json.Marshal(key.JWK)
+	if err != nil {
+		return nil, err
+	}
+
+	serializedPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields, payloadFields map[string]json.RawMessage
+	if err = json.Unmarshal(serialized, &fields); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(serializedPayload, &payloadFields); err != nil {
+		return nil, err
+	}
+
+	for name, value := range payloadFields {
+		fields[name] = value
+	}
+
+	return json.Marshal(fields)
+}
+
+func (key *Key) UnmarshalJSON(data []byte) error {
+	var decoded Key
+
+	if err := json.Unmarshal(data, &decoded.JWK); err != nil {
+		return err
+	}
+
+	material, err := decodeKey(decoded.KTY, data)
+	if err != nil {
+		return err
+	}
+
+	decoded.Key = material
+	*key = decoded
+
+	return nil
+}
+
+// encodeKey returns the key type and the JWK payload of a cryptographic key.
+func encodeKey(key any) (jwa.KTY, any, error) {
+	switch typed := key.(type) {
+	case []byte:
+		return jwa.KTYOct, EncodeOct(typed), nil
+	case *rsa.PublicKey:
+		return jwa.KTYRSA, EncodeRSA(typed), nil
+	case *rsa.PrivateKey:
+		return jwa.KTYRSA, EncodeRSA(typed), nil
+	case *ecdsa.PublicKey:
+		payload, err := EncodeEC(typed)
+		return jwa.KTYEC, payload, err
+	case *ecdsa.PrivateKey:
+		payload, err := EncodeEC(typed)
+		return jwa.KTYEC, payload, err
+	case ed25519.PublicKey:
+		return jwa.KTYOKP, EncodeED(typed), nil
+	case ed25519.PrivateKey:
+		return jwa.KTYOKP, EncodeED(typed), nil
+	case *ecdh.PublicKey:
+		if typed.Curve() != ecdh.X25519() {
+			return "", nil, ErrUnsupportedCurve
+		}
+
+		payload, err := EncodeECDH(typed)
+
+		return jwa.KTYOKP, payload, err
+	case *ecdh.PrivateKey:
+		if typed.Curve() != ecdh.X25519() {
+			return "", nil, ErrUnsupportedCurve
+		}
+
+		payload, err := EncodeECDH(typed)
+
+		return jwa.KTYOKP, payload, err
+	default:
+		return "", nil, fmt.Errorf("%w: %T", ErrUnsupportedKeyType, key)
+	}
+}
+
+// decodeKey reads the key material of a serialized JWK, according to its key type. Private keys are returned
+// when the JWK holds private material, public keys otherwise.
+func decodeKey(kty jwa.KTY, data []byte) (any, error) {
+	switch kty {
+	case jwa.KTYOct:
+		var payload OctPayload
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+
+		return DecodeOct(&payload)
+	case jwa.KTYRSA:
+		var payload RSAPayload
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+
+		priv, pub, err := DecodeRSA(&payload)
+
+		return pickKey(payload.D != "", priv, pub, err)
+	case jwa.KTYEC:
+		var payload ECPayload
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+
+		priv, pub, err := DecodeEC(&payload)
+
+		return pickKey(payload.D != "", priv, pub, err)
+	case jwa.KTYOKP:
+		return decodeOKP(data)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedKeyType, kty)
+	}
+}
+
+// decodeOKP reads the key material of an Octet Key Pair, according to its curve.
+//
+// https://datatracker.ietf.org/doc/html/rfc8037#section-2
+func decodeOKP(data []byte) (any, error) {
+	var okp okpPayload
+	if err := json.Unmarshal(data, &okp); err != nil {
+		return nil, err
+	}
+
+	switch okp.Crv {
+	case "Ed25519":
+		var payload EDPayload
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+
+		priv, pub, err := DecodeED(&payload)
+
+		return pickKey(payload.D != "", priv, pub, err)
+	case "X25519":
+		var payload ECDHPayload
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+
+		priv, pub, err := DecodeECDH(&payload)
+
+		return pickKey(payload.D != "", priv, pub, err)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCurve, okp.Crv)
+	}
+}
+
+// pickKey returns the private key if the payload holds private material, and the public key otherwise.
+func pickKey[Priv, Pub any](private bool, priv Priv, pub Pub, err error) (any, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	if private {
+		return priv, nil
+	}
+
+	return pub, nil
+}
@@ -0,0 +1,235 @@
+package jwkjson_test
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+	jwkjson "github.com/a-novel-kit/jwt-core/jwk/json"
+)
+
+func TestKey(t *testing.T) {
+	octKey, err := jwkgen.HMAC(jwkgen.H256KeySize)
+	require.NoError(t, err)
+
+	rsaKey, err := jwkgen.RSA(jwkgen.RS256KeySize)
+	require.NoError(t, err)
+
+	ecKey, err := jwkgen.EC(elliptic.P384())
+	require.NoError(t, err)
+
+	edPrivKey, edPubKey, err := jwkgen.ED25519()
+	require.NoError(t, err)
+
+	ecdhKey, err := jwkgen.X25519()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		key any
+
+		expectKTY  jwa.KTY
+		expectPriv bool
+	}{
+		{
+			name:       "oct",
+			key:        octKey,
+			expectKTY:  jwa.KTYOct,
+			expectPriv: true,
+		},
+		{
+			name:       "RSA private",
+			key:        rsaKey,
+			expectKTY:  jwa.KTYRSA,
+			expectPriv: true,
+		},
+		{
+			name:      "RSA public",
+			key:       &rsaKey.PublicKey,
+			expectKTY: jwa.KTYRSA,
+		},
+		{
+			name:       "EC private",
+			key:        ecKey,
+			expectKTY:  jwa.KTYEC,
+			expectPriv: true,
+		},
+		{
+			name:      "EC public",
+			key:       &ecKey.PublicKey,
+			expectKTY: jwa.KTYEC,
+		},
+		{
+			name:       "Ed25519 private",
+			key:        edPrivKey,
+			expectKTY:  jwa.KTYOKP,
+			expectPriv: true,
+		},
+		{
+			name:      "Ed25519 public",
+			key:       edPubKey,
+			expectKTY: jwa.KTYOKP,
+		},
+		{
+			name:       "X25519 private",
+			key:        ecdhKey,
+			expectKTY:  jwa.KTYOKP,
+			expectPriv: true,
+		},
+		{
+			name:      "X25519 public",
+			key:       ecdhKey.PublicKey(),
+			expectKTY: jwa.KTYOKP,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			key := jwkjson.Key{
+				JWK: jwa.JWK{KID: "key-1", Use: jwa.UseSig},
+				Key: testCase.key,
+			}
+
+			serialized, err := json.Marshal(key)
+			require.NoError(t, err)
+
+			// Metadata and key material are merged into a single object.
+			var fields map[string]any
+			require.NoError(t, json.Unmarshal(serialized, &fields))
+			require.Equal(t, string(testCase.expectKTY), fields["kty"])
+			require.Equal(t, "key-1", fields["kid"])
+
+			var decoded jwkjson.Key
+			require.NoError(t, json.Unmarshal(serialized, &decoded))
+
+			require.Equal(t, testCase.expectKTY, decoded.KTY)
+			require.Equal(t, "key-1", decoded.KID)
+			require.Equal(t, jwa.UseSig, decoded.Use)
+			requireKeyEqual(t, testCase.key, decoded.Key)
+			require.Equal(t, testCase.expectPriv, decoded.IsPrivate())
+			requireKeyEqual(t, key.Public(), decoded.Public())
+		})
+	}
+}
+
+// requireKeyEqual compares keys with their Equal method, since their internal precomputed values may differ.
+func requireKeyEqual(t *testing.T, expect, actual any) {
+	t.Helper()
+
+	switch typed := expect.(type) {
+	case interface {
+		Equal(x crypto.PrivateKey) bool
+	}:
+		require.True(t, typed.Equal(actual))
+	case interface{ Equal(x crypto.PublicKey) bool }:
+		require.True(t, typed.Equal(actual))
+	default:
+		require.Equal(t, expect, actual)
+	}
+}
+
+func TestKeyUnmarshal(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		data string
+
+		expectType any
+		expectErr  error
+	}{
+		{
+			// https://datatracker.ietf.org/doc/html/rfc7517#appendix-A.1
+			name: "EC public key",
+			data: `{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4",` +
+				`"y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM","use":"enc","kid":"1"}`,
+			expectType: &ecdsa.PublicKey{},
+		},
+		{
+			// https://datatracker.ietf.org/doc/html/rfc7517#appendix-A.1
+			name: "RSA public key",
+			data: `{"kty":"RSA","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6` +
+				`tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQM` +
+				`icAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-` +
+				`G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw","e":"AQAB","alg":"RS256","kid":"2011-04-29"}`,
+			expectType: &rsa.PublicKey{},
+		},
+		{
+			// https://datatracker.ietf.org/doc/html/rfc8037#appendix-A.6
+			name:       "X25519 public key",
+			data:       `{"kty":"OKP","crv":"X25519","x":"hSDwCYkwp1R0i33ctD73Wg2_Og0mOBr066SpjqqbTmo"}`,
+			expectType: &ecdh.PublicKey{},
+		},
+		{
+			name:      "unsupported key type",
+			data:      `{"kty":"foo"}`,
+			expectErr: jwkjson.ErrUnsupportedKeyType,
+		},
+		{
+			name:      "unsupported OKP curve",
+			data:      `{"kty":"OKP","crv":"Ed448","x":"foo"}`,
+			expectErr: jwkjson.ErrUnsupportedCurve,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var key jwkjson.Key
+
+			err := json.Unmarshal([]byte(testCase.data), &key)
+			require.ErrorIs(t, err, testCase.expectErr)
+
+			if testCase.expectType != nil {
+				require.IsType(t, testCase.expectType, key.Key)
+			}
+		})
+	}
+}
+
+func TestKeyMarshal(t *testing.T) {
+	octKey, err := jwkgen.HMAC(jwkgen.H256KeySize)
+	require.NoError(t, err)
+
+	p256Key, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		key jwkjson.Key
+
+		expectErr error
+	}{
+		{
+			name:      "key type mismatch",
+			key:       jwkjson.Key{JWK: jwa.JWK{KTY: jwa.KTYRSA}, Key: octKey},
+			expectErr: jwkjson.ErrKeyTypeMismatch,
+		},
+		{
+			name:      "unsupported ECDH curve",
+			key:       jwkjson.Key{Key: p256Key},
+			expectErr: jwkjson.ErrUnsupportedCurve,
+		},
+		{
+			name:      "unsupported key",
+			key:       jwkjson.Key{Key: "foo"},
+			expectErr: jwkjson.ErrUnsupportedKeyType,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := json.Marshal(testCase.key)
+			require.ErrorIs(t, err, testCase.expectErr)
+		})
+	}
+}
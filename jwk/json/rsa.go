@@ -3,10 +3,21 @@ package jwkjson
 import (
 	"crypto/rsa"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
 )
 
+// MinRSAKeySize is the minimum size, in bits, of the modulus of a RSA key accepted by DecodeRSA.
+//
+// https://datatracker.ietf.org/doc/html/rfc7518#section-3.3
+//
+// A key of size 2048 bits or larger MUST be used with these algorithms.
+const MinRSAKeySize = 2048
+
+var ErrInvalidRSAKey = errors.New("invalid RSA key")
+
 type RSAOtherPrime struct {
 	// R prime factor.
 	//
@@ -133,6 +144,9 @@ type RSAPayload struct {
 }
 
 // DecodeRSA takes the representation of a RSAPayload and computes the key it contains.
+//
+// The key is validated on import: the modulus must be at least MinRSAKeySize bits long, the public exponent must be
+// an odd value between 3 and 2^31-1, and the private parameters must be consistent with the public ones.
 func DecodeRSA(src *RSAPayload) (*rsa.PrivateKey, *rsa.PublicKey, error) {
 	n, err := base64.RawURLEncoding.DecodeString(src.N)
 	if err != nil {
@@ -144,9 +158,9 @@ func DecodeRSA(src *RSAPayload) (*rsa.PrivateKey, *rsa.PublicKey, error) {
 		return nil, nil, fmt.Errorf("decode rsa key exponent: %w", err)
 	}
 
-	keyPub := &rsa.PublicKey{
-		N: new(big.Int).SetBytes(n),
-		E: int(new(big.Int).SetBytes(e).Int64()),
+	keyPub, err := newRSAPublicKey(n, e)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	if src.D == "" {
@@ -184,62 +198,90 @@ func DecodeRSA(src *RSAPayload) (*rsa.PrivateKey, *rsa.PublicKey, error) {
 	}
 
 	key := &rsa.PrivateKey{
-		PublicKey: rsa.PublicKey{
-			N: new(big.Int).SetBytes(n),
-			E: int(new(big.Int).SetBytes(e).Int64()),
-		},
-		D: new(big.Int).SetBytes(d),
+		PublicKey: *keyPub,
+		D:         new(big.Int).SetBytes(d),
 		Primes: []*big.Int{
 			new(big.Int).SetBytes(p),
 			new(big.Int).SetBytes(q),
 		},
-		Precomputed: rsa.PrecomputedValues{
-			Dp:   new(big.Int).SetBytes(dp),
-			Dq:   new(big.Int).SetBytes(dq),
-			Qinv: new(big.Int).SetBytes(qi),
-		},
 	}
 
-	if len(src.Oth) > 0 {
-		key.Precomputed.CRTValues = make([]rsa.CRTValue, len(src.Oth)+2) //nolint:staticcheck
+	otherCRT := make([]rsa.CRTValue, len(src.Oth)) //nolint:staticcheck
 
-		key.Precomputed.CRTValues[0] = rsa.CRTValue{ //nolint:staticcheck
-			Exp:   new(big.Int).SetBytes(dp),
-			Coeff: new(big.Int).SetBytes(qi),
+	for i, oth := range src.Oth {
+		OR, err := base64.RawURLEncoding.DecodeString(oth.R)
+		if err != nil {
+			return nil, nil, fmt.Errorf("decode rsa private key other prime factor %d: %w", i, err)
 		}
 
-		key.Precomputed.CRTValues[1] = rsa.CRTValue{ //nolint:staticcheck
-			Exp:   new(big.Int).SetBytes(dq),
-			Coeff: new(big.Int).SetBytes(qi),
+		OD, err := base64.RawURLEncoding.DecodeString(oth.D)
+		if err != nil {
+			return nil, nil, fmt.Errorf("decode rsa private key other prime factor %d CRT exponent: %w", i, err)
 		}
 
-		for i, oth := range src.Oth {
-			OR, err := base64.RawURLEncoding.DecodeString(oth.R)
-			if err != nil {
-				return nil, nil, fmt.Errorf("decode rsa private key other prime factor %d: %w", i, err)
-			}
+		OT, err := base64.RawURLEncoding.DecodeString(oth.T)
+		if err != nil {
+			return nil, nil, fmt.Errorf("decode rsa private key other prime factor %d CRT coefficient: %w", i, err)
+		}
 
-			OD, err := base64.RawURLEncoding.DecodeString(oth.D)
-			if err != nil {
-				return nil, nil, fmt.Errorf("decode rsa private key other prime factor %d CRT exponent: %w", i, err)
-			}
+		key.Primes = append(key.Primes, new(big.Int).SetBytes(OR))
+		otherCRT[i] = rsa.CRTValue{Exp: new(big.Int).SetBytes(OD), Coeff: new(big.Int).SetBytes(OT)} //nolint:staticcheck
+	}
 
-			OT, err := base64.RawURLEncoding.DecodeString(oth.T)
-			if err != nil {
-				return nil, nil, fmt.Errorf("decode rsa private key other prime factor %d CRT coefficient: %w", i, err)
-			}
+	if err = key.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidRSAKey, err)
+	}
 
-			key.Precomputed.CRTValues[i+2] = rsa.CRTValue{ //nolint:staticcheck
-				R:     new(big.Int).SetBytes(OR),
-				Exp:   new(big.Int).SetBytes(OD),
-				Coeff: new(big.Int).SetBytes(OT),
-			}
+	// Validate does not check the primes of multi-prime keys.
+	product := big.NewInt(1)
+	for _, prime := range key.Primes {
+		product.Mul(product, prime)
+	}
+
+	if product.Cmp(key.N) != 0 {
+		return nil, nil, fmt.Errorf("%w: primes are inconsistent with the modulus", ErrInvalidRSAKey)
+	}
+
+	// The CRT values are computed from the primes, and must match the ones provided by the payload.
+	key.Precompute()
+
+	if key.Precomputed.Dp.Cmp(new(big.Int).SetBytes(dp)) != 0 ||
+		key.Precomputed.Dq.Cmp(new(big.Int).SetBytes(dq)) != 0 ||
+		key.Precomputed.Qinv.Cmp(new(big.Int).SetBytes(qi)) != 0 {
+		return nil, nil, fmt.Errorf("%w: CRT values are inconsistent with the key", ErrInvalidRSAKey)
+	}
+
+	for i, crt := range otherCRT {
+		actual := key.Precomputed.CRTValues[i] //nolint:staticcheck
+		if actual.Exp.Cmp(crt.Exp) != 0 || actual.Coeff.Cmp(crt.Coeff) != 0 {
+			return nil, nil, fmt.Errorf("%w: CRT values of prime %d are inconsistent with the key", ErrInvalidRSAKey, i)
 		}
 	}
 
 	return key, keyPub, nil
 }
 
+// newRSAPublicKey creates a RSA public key from its decoded modulus and exponent, rejecting weak parameters.
+func newRSAPublicKey(n, e []byte) (*rsa.PublicKey, error) {
+	modulus := new(big.Int).SetBytes(n)
+	if modulus.BitLen() < MinRSAKeySize {
+		return nil, fmt.Errorf(
+			"%w: modulus is %d bits long, at least %d are required", ErrInvalidRSAKey, modulus.BitLen(), MinRSAKeySize,
+		)
+	}
+
+	if modulus.Bit(0) == 0 {
+		return nil, fmt.Errorf("%w: modulus must be odd", ErrInvalidRSAKey)
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > math.MaxInt32 || exponent.Bit(0) == 0 {
+		return nil, fmt.Errorf("%w: invalid public exponent %s", ErrInvalidRSAKey, exponent)
+	}
+
+	return &rsa.PublicKey{N: modulus, E: int(exponent.Int64())}, nil
+}
+
 // EncodeRSA takes a key and create a RSAPayload representation of it.
 func EncodeRSA[Key *rsa.PublicKey | *rsa.PrivateKey](key Key) *RSAPayload {
 	payload := new(RSAPayload)
@@ -268,12 +310,13 @@ func EncodeRSA[Key *rsa.PublicKey | *rsa.PrivateKey](key Key) *RSAPayload {
 	payload.DQ = base64.RawURLEncoding.EncodeToString(privKey.Precomputed.Dq.Bytes())
 	payload.QI = base64.RawURLEncoding.EncodeToString(privKey.Precomputed.Qinv.Bytes())
 
+	// CRTValues only holds the values of the primes after the first two.
 	if len(privKey.Precomputed.CRTValues) > 0 { //nolint:staticcheck
-		payload.Oth = make([]RSAOtherPrime, len(privKey.Precomputed.CRTValues)-2) //nolint:staticcheck
+		payload.Oth = make([]RSAOtherPrime, len(privKey.Precomputed.CRTValues)) //nolint:staticcheck
 
-		for i, crt := range privKey.Precomputed.CRTValues[2:] { //nolint:staticcheck
+		for i, crt := range privKey.Precomputed.CRTValues { //nolint:staticcheck
 			payload.Oth[i] = RSAOtherPrime{
-				R: base64.RawURLEncoding.EncodeToString(crt.R.Bytes()),
+				R: base64.RawURLEncoding.EncodeToString(privKey.Primes[i+2].Bytes()),
 				D: base64.RawURLEncoding.EncodeToString(crt.Exp.Bytes()),
 				T: base64.RawURLEncoding.EncodeToString(crt.Coeff.Bytes()),
 			}
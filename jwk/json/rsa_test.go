@@ -1,6 +1,8 @@
 package jwkjson_test
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -43,3 +45,97 @@ func TestEncodeDecodeRSAPublic(t *testing.T) {
 		require.True(t, key1.PublicKey.Equal(decodedPub))
 	})
 }
+
+func TestDecodeRSAValidation(t *testing.T) {
+	key, err := jwkgen.RSA(jwkgen.RS256KeySize)
+	require.NoError(t, err)
+
+	otherKey, err := jwkgen.RSA(jwkgen.RS256KeySize)
+	require.NoError(t, err)
+
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	encoded := jwkjson.EncodeRSA(key)
+	encodedOther := jwkjson.EncodeRSA(otherKey)
+
+	// withPayload returns a copy of the encoded key, modified by the update function.
+	withPayload := func(update func(payload *jwkjson.RSAPayload)) *jwkjson.RSAPayload {
+		payload := *encoded
+		update(&payload)
+
+		return &payload
+	}
+
+	testCases := []struct {
+		name string
+
+		payload *jwkjson.RSAPayload
+	}{
+		{
+			name:    "weak modulus",
+			payload: jwkjson.EncodeRSA(&weakKey.PublicKey),
+		},
+		{
+			name: "exponent too small",
+			payload: withPayload(func(payload *jwkjson.RSAPayload) {
+				payload.D = ""
+				payload.E = "AQ"
+			}),
+		},
+		{
+			name: "even exponent",
+			payload: withPayload(func(payload *jwkjson.RSAPayload) {
+				payload.D = ""
+				payload.E = "AQAA"
+			}),
+		},
+		{
+			name: "exponent too large",
+			payload: withPayload(func(payload *jwkjson.RSAPayload) {
+				payload.D = ""
+				payload.E = "AQAAAAE"
+			}),
+		},
+		{
+			name: "private exponent mismatch",
+			payload: withPayload(func(payload *jwkjson.RSAPayload) {
+				payload.D = encodedOther.D
+			}),
+		},
+		{
+			name: "primes mismatch",
+			payload: withPayload(func(payload *jwkjson.RSAPayload) {
+				payload.P = encodedOther.P
+			}),
+		},
+		{
+			name: "CRT values mismatch",
+			payload: withPayload(func(payload *jwkjson.RSAPayload) {
+				payload.DP, payload.DQ = payload.DQ, payload.DP
+			}),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			decodedPriv, decodedPub, err := jwkjson.DecodeRSA(testCase.payload)
+			require.ErrorIs(t, err, jwkjson.ErrInvalidRSAKey)
+			require.Nil(t, decodedPriv)
+			require.Nil(t, decodedPub)
+		})
+	}
+}
+
+func TestEncodeDecodeRSAMultiPrime(t *testing.T) {
+	key, err := rsa.GenerateMultiPrimeKey(rand.Reader, 3, 3072) //nolint:staticcheck
+	require.NoError(t, err)
+
+	encoded := jwkjson.EncodeRSA(key)
+	require.Len(t, encoded.Oth, 1)
+
+	decodedPriv, decodedPub, err := jwkjson.DecodeRSA(encoded)
+	require.NoError(t, err)
+	require.True(t, key.Equal(decodedPriv))
+	require.True(t, key.PublicKey.Equal(decodedPub))
+}
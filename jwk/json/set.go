@@ -0,0 +1,156 @@
+package jwkjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+)
+
+var ErrMalformedSet = errors.New("malformed JWK set")
+
+// Set is a JWK Set, that represents a set of keys.
+//
+// https://datatracker.ietf.org/doc/html/rfc7517#section-5
+//
+// A JWK Set is a JSON object that represents a set of JWKs. The JSON
+// object MUST have a "keys" member, with its value being an array of
+// JWKs.
+//
+// Implementations SHOULD ignore JWKs within a JWK Set that use "kty"
+// (key type) values that are not understood by them, that are missing
+// required members, or for which values are out of the supported
+// ranges.
+type Set struct {
+	// Keys is the list of keys in the set.
+	Keys []*Key `json:"keys"`
+
+	// Skipped lists the keys that were ignored when the set was decoded. It is never serialized.
+	Skipped []SkippedKey `json:"-"`
+}
+
+// SkippedKey reports a key of a JWK Set that could not be decoded.
+type SkippedKey struct {
+	// Index is the position of the key in the "keys" array.
+	Index int
+	// Raw is the serialized key.
+	Raw json.RawMessage
+	// Err is the reason the key was skipped.
+	Err error
+}
+
+// KeyQuery selects keys in a Set. Empty criteria are ignored.
+//
+// Keys that do not restrict their "use", "alg" or "key_ops" match any value for this parameter. The "kid"
+// parameter, however, must always be an exact match when set in the query.
+type KeyQuery struct {
+	KID    string
+	Use    jwa.Use
+	Alg    jwa.Alg
+	KTY    jwa.KTY
+	KeyOps []jwa.KeyOp
+}
+
+// Match returns true if the key metadata matches every criterion of the query.
+func (query *KeyQuery) Match(key *jwa.JWK) bool {
+	if query.KID != "" && key.KID != query.KID {
+		return false
+	}
+
+	if query.KTY != "" && key.KTY != query.KTY {
+		return false
+	}
+
+	if query.Use != "" && key.Use != "" && key.Use != query.Use {
+		return false
+	}
+
+	if query.Alg != "" && key.Alg != "" && key.Alg != query.Alg {
+		return false
+	}
+
+	if len(key.KeyOps) > 0 {
+		for _, op := range query.KeyOps {
+			if !slices.Contains(key.KeyOps, op) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Find returns the keys of the set that match the query, in their order of appearance. A nil query matches every
+// key. The returned slice is a copy, and can be modified without altering the set.
+func (set *Set) Find(query *KeyQuery) []*Key {
+	if query == nil {
+		return slices.Clone(set.Keys)
+	}
+
+	var output []*Key
+
+	for _, key := range set.Keys {
+		if query.Match(&key.JWK) {
+			output = append(output, key)
+		}
+	}
+
+	return output
+}
+
+// Get returns the key with the given "kid" parameter, or nil if there is none.
+func (set *Set) Get(kid string) *Key {
+	for _, key := range set.Keys {
+		if key.KID == kid {
+			return key
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON serializes the set. A set without keys is serialized with an empty "keys" array.
+func (set Set) MarshalJSON() ([]byte, error) {
+	keys := set.Keys
+	if keys == nil {
+		keys = []*Key{}
+	}
+
+	return json.Marshal(struct {
+		Keys []*Key `json:"keys"`
+	}{Keys: keys})
+}
+
+// UnmarshalJSON decodes a JWK set. Keys that cannot be decoded are not returned as an error, but are listed in
+// the Skipped member of the set instead.
+func (set *Set) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedSet, err)
+	}
+
+	if raw.Keys == nil {
+		return fmt.Errorf("%w: missing keys member", ErrMalformedSet)
+	}
+
+	var decoded Set
+
+	for i, rawKey := range raw.Keys {
+		key := new(Key)
+		if err := json.Unmarshal(rawKey, key); err != nil {
+			decoded.Skipped = append(decoded.Skipped, SkippedKey{Index: i, Raw: rawKey, Err: err})
+			continue
+		}
+
+		decoded.Keys = append(decoded.Keys, key)
+	}
+
+	*set = decoded
+
+	return nil
+}
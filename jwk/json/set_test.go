@@ -0,0 +1,156 @@
+package jwkjson_test
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+	jwkjson "github.com/a-novel-kit/jwt-core/jwk/json"
+)
+
+func TestSetUnmarshal(t *testing.T) {
+	// https://datatracker.ietf.org/doc/html/rfc7517#appendix-A.1, with an additional key of an unsupported type.
+	data := `{"keys":[` +
+		`{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4",` +
+		`"y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM","use":"enc","kid":"1"},` +
+		`{"kty":"foo","kid":"2"},` +
+		`{"kty":"RSA","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECP` +
+		`ebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJ` +
+		`ZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1` +
+		`jF44-csFCur-kEgU8awapJzKnqDKgw","e":"AQAB","alg":"RS256","kid":"2011-04-29"}` +
+		`]}`
+
+	var set jwkjson.Set
+	require.NoError(t, json.Unmarshal([]byte(data), &set))
+
+	require.Len(t, set.Keys, 2)
+	require.Equal(t, "1", set.Keys[0].KID)
+	require.Equal(t, "2011-04-29", set.Keys[1].KID)
+
+	require.Len(t, set.Skipped, 1)
+	require.Equal(t, 1, set.Skipped[0].Index)
+	require.JSONEq(t, `{"kty":"foo","kid":"2"}`, string(set.Skipped[0].Raw))
+	require.ErrorIs(t, set.Skipped[0].Err, jwkjson.ErrUnsupportedKeyType)
+
+	t.Run("malformed", func(t *testing.T) {
+		var set jwkjson.Set
+		require.ErrorIs(t, json.Unmarshal([]byte(`{"keys":"foo"}`), &set), jwkjson.ErrMalformedSet)
+		require.ErrorIs(t, json.Unmarshal([]byte(`{}`), &set), jwkjson.ErrMalformedSet)
+	})
+}
+
+func TestSetMarshal(t *testing.T) {
+	ecKey, err := jwkgen.EC(elliptic.P256())
+	require.NoError(t, err)
+
+	_, edKey, err := jwkgen.ED25519()
+	require.NoError(t, err)
+
+	set := jwkjson.Set{Keys: []*jwkjson.Key{
+		{JWK: jwa.JWK{KID: "ec"}, Key: &ecKey.PublicKey},
+		{JWK: jwa.JWK{KID: "ed"}, Key: edKey},
+	}}
+
+	serialized, err := json.Marshal(set)
+	require.NoError(t, err)
+
+	var decoded jwkjson.Set
+	require.NoError(t, json.Unmarshal(serialized, &decoded))
+	require.Empty(t, decoded.Skipped)
+	require.Len(t, decoded.Keys, 2)
+	require.True(t, ecKey.PublicKey.Equal(decoded.Get("ec").Key))
+	require.Equal(t, edKey, decoded.Get("ed").Key)
+	require.Nil(t, decoded.Get("foo"))
+
+	t.Run("empty", func(t *testing.T) {
+		serialized, err := json.Marshal(jwkjson.Set{})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"keys":[]}`, string(serialized))
+	})
+}
+
+func TestSetFind(t *testing.T) {
+	hmacKey, err := jwkgen.HMAC(jwkgen.H256KeySize)
+	require.NoError(t, err)
+
+	set := jwkjson.Set{Keys: []*jwkjson.Key{
+		{JWK: jwa.JWK{KTY: jwa.KTYOct, KID: "sig", Use: jwa.UseSig, Alg: jwa.HS256}, Key: hmacKey},
+		{JWK: jwa.JWK{KTY: jwa.KTYOct, KID: "enc", Use: jwa.UseEnc}, Key: hmacKey},
+		{JWK: jwa.JWK{KTY: jwa.KTYOct, KID: "ops", KeyOps: []jwa.KeyOp{jwa.KeyOpSign, jwa.KeyOpVerify}}, Key: hmacKey},
+		{JWK: jwa.JWK{KTY: jwa.KTYOct, KID: "any"}, Key: hmacKey},
+	}}
+
+	testCases := []struct {
+		name string
+
+		query *jwkjson.KeyQuery
+
+		expect []string
+	}{
+		{
+			name:   "nil query",
+			expect: []string{"sig", "enc", "ops", "any"},
+		},
+		{
+			name:   "kid",
+			query:  &jwkjson.KeyQuery{KID: "enc"},
+			expect: []string{"enc"},
+		},
+		{
+			name:   "use",
+			query:  &jwkjson.KeyQuery{Use: jwa.UseSig},
+			expect: []string{"sig", "ops", "any"},
+		},
+		{
+			name:   "alg",
+			query:  &jwkjson.KeyQuery{Alg: jwa.HS512},
+			expect: []string{"enc", "ops", "any"},
+		},
+		{
+			name:   "key ops",
+			query:  &jwkjson.KeyQuery{KeyOps: []jwa.KeyOp{jwa.KeyOpVerify}},
+			expect: []string{"sig", "enc", "ops", "any"},
+		},
+		{
+			name:   "unavailable key ops",
+			query:  &jwkjson.KeyQuery{KID: "ops", KeyOps: []jwa.KeyOp{jwa.KeyOpEncrypt}},
+			expect: nil,
+		},
+		{
+			name:   "kty",
+			query:  &jwkjson.KeyQuery{KTY: jwa.KTYRSA},
+			expect: nil,
+		},
+		{
+			name:   "combined",
+			query:  &jwkjson.KeyQuery{Use: jwa.UseSig, Alg: jwa.HS256, KeyOps: []jwa.KeyOp{jwa.KeyOpSign}},
+			expect: []string{"sig", "ops", "any"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var kids []string
+			for _, key := range set.Find(testCase.query) {
+				kids = append(kids, key.KID)
+			}
+
+			require.Equal(t, testCase.expect, kids)
+		})
+	}
+	t.Run("copy", func(t *testing.T) {
+		for _, query := range []*jwkjson.KeyQuery{nil, {}} {
+			found := set.Find(query)
+			require.Len(t, found, 4)
+
+			found[0], found[1] = nil, &jwkjson.Key{}
+
+			require.Equal(t, "sig", set.Keys[0].KID)
+			require.Equal(t, "enc", set.Keys[1].KID)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package jwkjson
+
+import (
+	"crypto"
+	_ "crypto/sha256" // Register the thumbprint hashes.
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrUnsupportedHash = errors.New("unsupported thumbprint hash")
+
+// thumbprintURIPrefix is the prefix of a JWK thumbprint URI.
+//
+// https://datatracker.ietf.org/doc/html/rfc9278#section-3
+const thumbprintURIPrefix = "urn:ietf:params:oauth:jwk-thumbprint:"
+
+// thumbprintHashNames maps the supported hashes to their name in the IANA "Named Information Hash Algorithm"
+// registry, used in thumbprint URIs.
+var thumbprintHashNames = map[crypto.Hash]string{
+	crypto.SHA256: "sha-256",
+	crypto.SHA384: "sha-384",
+	crypto.SHA512: "sha-512",
+}
+
+// thumbprintMember is a member of the thumbprint input.
+type thumbprintMember struct {
+	name  string
+	value string
+}
+
+// Thumbprint computes the JWK thumbprint of the key, and returns it base64url-encoded. Supported hashes are SHA-256,
+// SHA-384 and SHA-512.
+//
+// https://datatracker.ietf.org/doc/html/rfc7638#section-3
+//
+// The thumbprint of a JSON Web Key (JWK) is computed as follows:
+//
+//  1. Construct a JSON object [RFC7159] containing only the required
+//     members of a JWK representing the key and with no whitespace or
+//     line breaks before or after any syntactic elements and with the
+//     required members ordered lexicographically by the Unicode
+//     [UNICODE] code points of the member names. (This JSON object is
+//     itself a legal JWK representation of the key.)
+//
+//  2. Hash the octets of the UTF-8 representation of this JSON object
+//     with a cryptographic hash function H. For example, SHA-256 [SHS]
+//     might be used as H.
+//
+// Only the public members are used, so a private key and its public counterpart have the same thumbprint.
+func (key *Key) Thumbprint(hash crypto.Hash) (string, error) {
+	if _, ok := thumbprintHashNames[hash]; !ok || !hash.Available() {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedHash, hash)
+	}
+
+	input, err := key.thumbprintInput()
+	if err != nil {
+		return "", err
+	}
+
+	hasher := hash.New()
+	hasher.Write(input)
+
+	return base64.RawURLEncoding.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// ThumbprintURI returns the JWK thumbprint URI of the key.
+//
+// https://datatracker.ietf.org/doc/html/rfc9278#section-3
+//
+// The JWK Thumbprint URI is of the form:
+//
+//	urn:ietf:params:oauth:jwk-thumbprint:<hash-algorithm>:<jwk-thumbprint>
+func (key *Key) ThumbprintURI(hash crypto.Hash) (string, error) {
+	thumbprint, err := key.Thumbprint(hash)
+	if err != nil {
+		return "", err
+	}
+
+	return thumbprintURIPrefix + thumbprintHashNames[hash] + ":" + thumbprint, nil
+}
+
+// thumbprintInput returns the JSON object hashed to compute the thumbprint of the key.
+//
+// https://datatracker.ietf.org/doc/html/rfc7638#section-3.2
+func (key *Key) thumbprintInput() ([]byte, error) {
+	kty, payload, err := encodeKey(key.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	if key.KTY != "" && key.KTY != kty {
+		return nil, fmt.Errorf("%w: kty is %q, key material is %q", ErrKeyTypeMismatch, key.KTY, kty)
+	}
+
+	var members []thumbprintMember
+
+	// Members are listed in lexicographic order.
+	switch typed := payload.(type) {
+	case *OctPayload:
+		members = []thumbprintMember{{"k", typed.K}, {"kty", string(kty)}}
+	case *RSAPayload:
+		members = []thumbprintMember{{"e", typed.E}, {"kty", string(kty)}, {"n", typed.N}}
+	case *ECPayload:
+		members = []thumbprintMember{{"crv", typed.Crv}, {"kty", string(kty)}, {"x", typed.X}, {"y", typed.Y}}
+	case *EDPayload:
+		members = []thumbprintMember{{"crv", typed.Crv}, {"kty", string(kty)}, {"x", typed.X}}
+	case *ECDHPayload:
+		members = []thumbprintMember{{"crv", typed.Crv}, {"kty", string(kty)}, {"x", typed.X}}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKeyType, kty)
+	}
+
+	return marshalThumbprintMembers(members)
+}
+
+// marshalThumbprintMembers serializes the members of a thumbprint input, in order, without whitespace.
+func marshalThumbprintMembers(members []thumbprintMember) ([]byte, error) {
+	output := []byte{'{'}
+
+	for i, member := range members {
+		if i > 0 {
+			output = append(output, ',')
+		}
+
+		name, err := json.Marshal(member.name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(member.value)
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, name...)
+		output = append(output, ':')
+		output = append(output, value...)
+	}
+
+	return append(output, '}'), nil
+}
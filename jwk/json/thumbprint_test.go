@@ -0,0 +1,116 @@
+package jwkjson_test
+
+import (
+	"crypto"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+	jwkjson "github.com/a-novel-kit/jwt-core/jwk/json"
+)
+
+func TestThumbprint(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		data string
+		hash crypto.Hash
+
+		expect    string
+		expectURI string
+		expectErr error
+	}{
+		{
+			// https://datatracker.ietf.org/doc/html/rfc7638#section-3.1
+			name: "RSA",
+			data: `{"kty":"RSA","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6` +
+				`tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQM` +
+				`icAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-` +
+				`G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw","e":"AQAB","alg":"RS256","kid":"2011-04-29"}`,
+			hash: crypto.SHA256,
+			// https://datatracker.ietf.org/doc/html/rfc9278#section-4
+			expect:    "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs",
+			expectURI: "urn:ietf:params:oauth:jwk-thumbprint:sha-256:NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs",
+		},
+		{
+			// https://datatracker.ietf.org/doc/html/rfc8037#appendix-A.3
+			name:      "Ed25519",
+			data:      `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`,
+			hash:      crypto.SHA256,
+			expect:    "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k",
+			expectURI: "urn:ietf:params:oauth:jwk-thumbprint:sha-256:kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k",
+		},
+		{
+			name:      "unsupported hash",
+			data:      `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`,
+			hash:      crypto.SHA1,
+			expectErr: jwkjson.ErrUnsupportedHash,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var key jwkjson.Key
+			require.NoError(t, json.Unmarshal([]byte(testCase.data), &key))
+
+			thumbprint, err := key.Thumbprint(testCase.hash)
+			require.ErrorIs(t, err, testCase.expectErr)
+			require.Equal(t, testCase.expect, thumbprint)
+
+			uri, err := key.ThumbprintURI(testCase.hash)
+			require.ErrorIs(t, err, testCase.expectErr)
+			require.Equal(t, testCase.expectURI, uri)
+		})
+	}
+}
+
+func TestThumbprintPrivateKey(t *testing.T) {
+	hmacKey, err := jwkgen.HMAC(jwkgen.H256KeySize)
+	require.NoError(t, err)
+
+	rsaKey, err := jwkgen.RSA(jwkgen.RS256KeySize)
+	require.NoError(t, err)
+
+	ecdhKey, err := jwkgen.X25519()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		private any
+		public  any
+	}{
+		{
+			name:    "oct",
+			private: hmacKey,
+			public:  hmacKey,
+		},
+		{
+			name:    "RSA",
+			private: rsaKey,
+			public:  &rsaKey.PublicKey,
+		},
+		{
+			name:    "X25519",
+			private: ecdhKey,
+			public:  ecdhKey.PublicKey(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			for _, hash := range []crypto.Hash{crypto.SHA256, crypto.SHA384, crypto.SHA512} {
+				privateThumbprint, err := (&jwkjson.Key{Key: testCase.private}).Thumbprint(hash)
+				require.NoError(t, err)
+
+				publicThumbprint, err := (&jwkjson.Key{Key: testCase.public}).Thumbprint(hash)
+				require.NoError(t, err)
+
+				require.Equal(t, publicThumbprint, privateThumbprint)
+				require.Len(t, privateThumbprint, (hash.Size()*8+5)/6)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package jwscore
+
+import (
+	"errors"
+	"fmt"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+)
+
+var (
+	ErrUnsupportedAlg   = errors.New("unsupported algorithm")
+	ErrInvalidKey       = errors.New("invalid key for algorithm")
+	ErrMalformedToken   = errors.New("malformed token")
+	ErrMissingSignature = errors.New("missing signature")
+	ErrUnsecuredToken   = errors.New("unsecured token")
+)
+
+// JWS is a JSON Web Signature, in its compact serialization.
+//
+// https://datatracker.ietf.org/doc/html/rfc7515#section-7.1
+type JWS struct {
+	// Header is the decoded JOSE header.
+	Header jwa.JWH
+	// Protected is the encoded JOSE header, as it appears in the token.
+	Protected string
+	// Payload is the decoded payload.
+	Payload []byte
+	// SigningInput is the raw input the signature was computed over, as it appears in the token:
+	//
+	//	ASCII(BASE64URL(UTF8(JWS Protected Header)) || '.' || BASE64URL(JWS Payload))
+	//
+	// If the "b64" header is false, the payload is not encoded.
+	SigningInput string
+	// Signature is the base64url-encoded signature.
+	Signature string
+}
+
+// Sign creates a new JWS in compact serialization. The algorithm is read from the "alg" header, and the key
+// must match the type expected by this algorithm:
+//
+//   - HS256, HS384, HS512: []byte
+//   - RS256, RS384, RS512, PS256, PS384, PS512: *rsa.PrivateKey, or a crypto.Signer backed by an RSA key
+//   - ES256, ES384, ES512: *ecdsa.PrivateKey, or a crypto.Signer backed by an ECDSA key
+//   - EdDSA: ed25519.PrivateKey
+//
+// If the "b64" header is false, the payload is not encoded (RFC 7797). It must then not contain any '.'
+// character.
+func Sign(header jwa.JWH, payload []byte, key any) (string, error) {
+	encodedHeader, err := jwtcore.Encode(header)
+	if err != nil {
+		return "", fmt.Errorf("encode header: %w", err)
+	}
+
+	encodedPayload, err := encodePayload(&header, payload)
+	if err != nil {
+		return "", err
+	}
+
+	if err = checkCompactPayload(encodedPayload); err != nil {
+		return "", err
+	}
+
+	unsigned := jwtcore.Assemble(encodedHeader, encodedPayload)
+
+	signature, err := sign(header.Alg, unsigned, key)
+	if err != nil {
+		return "", fmt.Errorf("sign token: %w", err)
+	}
+
+	return jwtcore.Assemble(unsigned, signature), nil
+}
+
+// ParseConfig customizes the parsing of a JWS.
+type ParseConfig struct {
+	// Critical lists the extension header parameters understood by the caller, which may appear in the "crit"
+	// header. The "b64" extension is always supported. A token listing any other parameter in its "crit" header
+	// is rejected with jwtcore.ErrUnsupportedCritical.
+	Critical []string
+	// Limits bounds the size of the token. If nil, the default limits are used.
+	Limits *jwtcore.Limits
+	// AllowUnsecured accepts compact tokens using the "none" algorithm. Those tokens carry no signature, and must
+	// only be trusted when their integrity is guaranteed by other means. Unless set, they are rejected with
+	// ErrUnsecuredToken. Accepted tokens must be verified with JWS.VerifyUnsecured.
+	AllowUnsecured bool
+}
+
+// critical returns the extension header parameters supported with this configuration.
+func (config *ParseConfig) critical() []string {
+	if config == nil {
+		return []string{b64Header}
+	}
+
+	return append([]string{b64Header}, config.Critical...)
+}
+
+// limits returns the size limits of this configuration.
+func (config *ParseConfig) limits() *jwtcore.Limits {
+	if config == nil {
+		return nil
+	}
+
+	return config.Limits
+}
+
+// allowUnsecured returns whether tokens using the "none" algorithm are accepted with this configuration.
+func (config *ParseConfig) allowUnsecured() bool {
+	return config != nil && config.AllowUnsecured
+}
+
+// Parse reads a JWS in compact serialization. It does not verify the signature: use JWS.Verify for this purpose.
+func Parse(token string) (*JWS, error) {
+	return ParseWithConfig(token, nil)
+}
+
+// ParseWithConfig works like Parse, with a custom configuration. A nil configuration uses the defaults.
+func ParseWithConfig(token string, config *ParseConfig) (*JWS, error) {
+	limits := config.limits()
+
+	if err := limits.CheckTokenSize(len(token)); err != nil {
+		return nil, err
+	}
+
+	parts, err := jwtcore.DisassembleN(token, 3)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: expected 3 segments, got %d", ErrMalformedToken, len(parts))
+	}
+
+	if err = limits.CheckHeaderSize(len(parts[0])); err != nil {
+		return nil, err
+	}
+
+	if err = limits.CheckPayloadSize(len(parts[1])); err != nil {
+		return nil, err
+	}
+
+	var header jwa.JWH
+	if err = jwtcore.DecodeStrict(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: decode header: %w", ErrMalformedToken, err)
+	}
+
+	if err = jwtcore.CheckCritical(parts[0], config.critical()); err != nil {
+		return nil, fmt.Errorf("check critical header: %w", err)
+	}
+
+	if header.Alg == "" {
+		return nil, fmt.Errorf("%w: missing alg header", ErrMalformedToken)
+	}
+
+	if header.Alg == jwa.None && !config.allowUnsecured() {
+		return nil, ErrUnsecuredToken
+	}
+
+	encoded, err := IsPayloadEncoded(&header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	payload := []byte(parts[1])
+	if encoded {
+		if payload, err = jwtcore.DecodeSegment(parts[1]); err != nil {
+			return nil, fmt.Errorf("%w: decode payload: %w", ErrMalformedToken, err)
+		}
+	}
+
+	return &JWS{
+		Header:       header,
+		Protected:    parts[0],
+		Payload:      payload,
+		SigningInput: jwtcore.Assemble(parts[0], parts[1]),
+		Signature:    parts[2],
+	}, nil
+}
+
+// Verify checks the signature of the token, using the algorithm from its "alg" header. The key must be the public
+// counterpart of the one expected by Sign (or the same secret, for HMAC).
+func (token *JWS) Verify(key any) error {
+	if token.Signature == "" {
+		return ErrMissingSignature
+	}
+
+	return verify(token.Header.Alg, token.SigningInput, token.Signature, key)
+}
+
+// VerifyPayload checks the signature of the token against an externally supplied payload, instead of the one
+// embedded in the token. It is used for detached content, where the payload segment of the token is empty.
+//
+// https://datatracker.ietf.org/doc/html/rfc7515#appendix-F
+func (token *JWS) VerifyPayload(payload []byte, key any) error {
+	if token.Signature == "" {
+		return ErrMissingSignature
+	}
+
+	unsigned, err := SigningInput(&token.Header, token.Protected, payload)
+	if err != nil {
+		return err
+	}
+
+	return verify(token.Header.Alg, unsigned, token.Signature, key)
+}
+
+// sign computes the signature of a signing input, with the Signer registered for the algorithm.
+func sign(alg jwa.Alg, unsigned string, key any) (string, error) {
+	signer, err := NewSigner(alg, key)
+	if err != nil {
+		return "", err
+	}
+
+	return signer.Sign(unsigned)
+}
+
+// verify checks the signature of a signing input, with the Verifier registered for the algorithm.
+func verify(alg jwa.Alg, unsigned, signature string, key any) error {
+	verifier, err := NewVerifier(alg, key)
+	if err != nil {
+		return err
+	}
+
+	return verifier.Verify(unsigned, signature)
+}
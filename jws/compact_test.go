@@ -0,0 +1,477 @@
+package jwscore_test
+
+import (
+	"crypto"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+	jwscore "github.com/a-novel-kit/jwt-core/jws"
+)
+
+func TestSignAndParse(t *testing.T) {
+	hmacKey, err := jwkgen.HMAC(jwkgen.H256KeySize)
+	require.NoError(t, err)
+
+	rsaKey, err := jwkgen.RSA(jwkgen.RS256KeySize)
+	require.NoError(t, err)
+
+	ecKey256, err := jwkgen.EC(elliptic.P256())
+	require.NoError(t, err)
+
+	ecKey521, err := jwkgen.EC(elliptic.P521())
+	require.NoError(t, err)
+
+	edPrivKey, edPubKey, err := jwkgen.ED25519()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		alg       jwa.Alg
+		signKey   any
+		verifyKey any
+	}{
+		{
+			name:      "HS256",
+			alg:       jwa.HS256,
+			signKey:   hmacKey,
+			verifyKey: hmacKey,
+		},
+		{
+			name:      "HS512",
+			alg:       jwa.HS512,
+			signKey:   hmacKey,
+			verifyKey: hmacKey,
+		},
+		{
+			name:      "RS256",
+			alg:       jwa.RS256,
+			signKey:   rsaKey,
+			verifyKey: &rsaKey.PublicKey,
+		},
+		{
+			name:      "PS384",
+			alg:       jwa.PS384,
+			signKey:   rsaKey,
+			verifyKey: &rsaKey.PublicKey,
+		},
+		{
+			name:      "ES256",
+			alg:       jwa.ES256,
+			signKey:   ecKey256,
+			verifyKey: &ecKey256.PublicKey,
+		},
+		{
+			name:      "ES512",
+			alg:       jwa.ES512,
+			signKey:   ecKey521,
+			verifyKey: &ecKey521.PublicKey,
+		},
+		{
+			name:      "EdDSA",
+			alg:       jwa.EdDSA,
+			signKey:   edPrivKey,
+			verifyKey: edPubKey,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			header := jwa.JWH{Alg: testCase.alg, Typ: jwa.TypJWT, KID: "key-1"}
+			payload := []byte(`{"sub":"user"}`)
+
+			token, err := jwscore.Sign(header, payload, testCase.signKey)
+			require.NoError(t, err)
+			require.Len(t, jwtcore.Disassemble(token), 3)
+
+			parsed, err := jwscore.Parse(token)
+			require.NoError(t, err)
+			require.Equal(t, header, parsed.Header)
+			require.Equal(t, payload, parsed.Payload)
+			require.NotEmpty(t, parsed.Signature)
+
+			parts := jwtcore.Disassemble(token)
+			require.Equal(t, jwtcore.Assemble(parts[0], parts[1]), parsed.SigningInput)
+
+			require.NoError(t, parsed.Verify(testCase.verifyKey))
+
+			t.Run("payload tampered", func(t *testing.T) {
+				tampered, err := jwscore.Parse(jwtcore.Assemble(parts[0], "e30", parts[2]))
+				require.NoError(t, err)
+				require.Error(t, tampered.Verify(testCase.verifyKey))
+			})
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	rsaKey, err := jwkgen.RSA(jwkgen.RS256KeySize)
+	require.NoError(t, err)
+
+	ecKey, err := jwkgen.EC(elliptic.P256())
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		alg jwa.Alg
+		key any
+
+		expect error
+	}{
+		{
+			name:   "unsupported algorithm",
+			alg:    jwa.Alg("foo"),
+			key:    []byte("secret"),
+			expect: jwscore.ErrUnsupportedAlg,
+		},
+		{
+			name:   "missing algorithm",
+			key:    []byte("secret"),
+			expect: jwscore.ErrUnsupportedAlg,
+		},
+		{
+			name:   "wrong key type",
+			alg:    jwa.HS256,
+			key:    rsaKey,
+			expect: jwscore.ErrInvalidKey,
+		},
+		{
+			name:   "public key",
+			alg:    jwa.RS256,
+			key:    &rsaKey.PublicKey,
+			expect: jwscore.ErrInvalidKey,
+		},
+		{
+			name:   "wrong curve",
+			alg:    jwa.ES384,
+			key:    ecKey,
+			expect: jwscore.ErrInvalidKey,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := jwscore.Sign(jwa.JWH{Alg: testCase.alg}, []byte("foo"), testCase.key)
+			require.ErrorIs(t, err, testCase.expect)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	hmacKey, err := jwkgen.HMAC(jwkgen.H256KeySize)
+	require.NoError(t, err)
+
+	token, err := jwscore.Sign(jwa.JWH{Alg: jwa.HS256}, []byte("foo"), hmacKey)
+	require.NoError(t, err)
+
+	parts := jwtcore.Disassemble(token)
+
+	noAlgHeader, err := jwtcore.Encode(jwa.JWH{Typ: jwa.TypJWT})
+	require.NoError(t, err)
+
+	duplicateHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","alg":"none"}`))
+	caseVariantHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"ALG":"HS256"}`))
+
+	testCases := []struct {
+		name string
+
+		token string
+
+		expect       error
+		expectVerify error
+	}{
+		{
+			name:  "ok",
+			token: token,
+		},
+		{
+			name:   "two segments",
+			token:  jwtcore.Assemble(parts[0], parts[1]),
+			expect: jwscore.ErrMalformedToken,
+		},
+		{
+			name:   "four segments",
+			token:  jwtcore.Assemble(parts[0], parts[1], parts[2], parts[2]),
+			expect: jwscore.ErrMalformedToken,
+		},
+		{
+			name:   "invalid header",
+			token:  jwtcore.Assemble("&/?", parts[1], parts[2]),
+			expect: jwscore.ErrMalformedToken,
+		},
+		{
+			name:   "missing alg",
+			token:  jwtcore.Assemble(noAlgHeader, parts[1], parts[2]),
+			expect: jwscore.ErrMalformedToken,
+		},
+		{
+			name:   "invalid payload",
+			token:  jwtcore.Assemble(parts[0], "&/?", parts[2]),
+			expect: jwscore.ErrMalformedToken,
+		},
+		{
+			name:   "duplicate header parameter",
+			token:  jwtcore.Assemble(duplicateHeader, parts[1], parts[2]),
+			expect: jwscore.ErrMalformedToken,
+		},
+		{
+			name:   "case variant of header parameter",
+			token:  jwtcore.Assemble(caseVariantHeader, parts[1], parts[2]),
+			expect: jwscore.ErrMalformedToken,
+		},
+		{
+			name:   "non-canonical payload",
+			token:  jwtcore.Assemble(parts[0], "Zh", parts[2]),
+			expect: jwscore.ErrMalformedToken,
+		},
+		{
+			name:         "empty signature",
+			token:        jwtcore.Assemble(parts[0], parts[1], ""),
+			expectVerify: jwscore.ErrMissingSignature,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			parsed, err := jwscore.Parse(testCase.token)
+			require.ErrorIs(t, err, testCase.expect)
+
+			if testCase.expect != nil {
+				return
+			}
+
+			require.ErrorIs(t, parsed.Verify(hmacKey), testCase.expectVerify)
+		})
+	}
+}
+
+func TestVerifyEmptySignature(t *testing.T) {
+	hmacKey, err := jwkgen.HMAC(jwkgen.H256KeySize)
+	require.NoError(t, err)
+
+	rsaKey, err := jwkgen.RSA(jwkgen.RS256KeySize)
+	require.NoError(t, err)
+
+	ecKey, err := jwkgen.EC(elliptic.P256())
+	require.NoError(t, err)
+
+	edPrivKey, edPubKey, err := jwkgen.ED25519()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		alg       jwa.Alg
+		signKey   any
+		verifyKey any
+	}{
+		{
+			name:      "HMAC",
+			alg:       jwa.HS256,
+			signKey:   hmacKey,
+			verifyKey: hmacKey,
+		},
+		{
+			name:      "RSA",
+			alg:       jwa.RS256,
+			signKey:   rsaKey,
+			verifyKey: &rsaKey.PublicKey,
+		},
+		{
+			name:      "RSA-PSS",
+			alg:       jwa.PS256,
+			signKey:   rsaKey,
+			verifyKey: &rsaKey.PublicKey,
+		},
+		{
+			name:      "ECDSA",
+			alg:       jwa.ES256,
+			signKey:   ecKey,
+			verifyKey: &ecKey.PublicKey,
+		},
+		{
+			name:      "EdDSA",
+			alg:       jwa.EdDSA,
+			signKey:   edPrivKey,
+			verifyKey: edPubKey,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			token, err := jwscore.Sign(jwa.JWH{Alg: testCase.alg}, []byte("foo"), testCase.signKey)
+			require.NoError(t, err)
+
+			parts := jwtcore.Disassemble(token)
+
+			parsed, err := jwscore.Parse(jwtcore.Assemble(parts[0], parts[1], ""))
+			require.NoError(t, err)
+			require.ErrorIs(t, parsed.Verify(testCase.verifyKey), jwscore.ErrMissingSignature)
+
+			verifier, err := jwscore.NewVerifier(testCase.alg, testCase.verifyKey)
+			require.NoError(t, err)
+			require.ErrorIs(t, verifier.Verify(jwtcore.Assemble(parts[0], parts[1]), ""), jwscore.ErrMissingSignature)
+		})
+	}
+}
+
+func TestParseWithConfig(t *testing.T) {
+	hmacKey, err := jwkgen.HMAC(jwkgen.H256KeySize)
+	require.NoError(t, err)
+
+	// The JWH type does not carry extension parameters, so the header is built by hand.
+	header, err := jwtcore.Encode(map[string]any{
+		"alg":                          "HS256",
+		"crit":                         []string{"http://example.com/UNDEFINED"},
+		"http://example.com/UNDEFINED": true,
+	})
+	require.NoError(t, err)
+
+	unsigned := jwtcore.Assemble(header, "Zm9v")
+
+	signature, err := jwscore.SignHMAC(unsigned, hmacKey, crypto.SHA256)
+	require.NoError(t, err)
+
+	token := jwtcore.Assemble(unsigned, signature)
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := jwscore.Parse(token)
+		require.ErrorIs(t, err, jwtcore.ErrUnsupportedCritical)
+	})
+
+	t.Run("supported", func(t *testing.T) {
+		parsed, err := jwscore.ParseWithConfig(token, &jwscore.ParseConfig{
+			Critical: []string{"http://example.com/UNDEFINED"},
+		})
+		require.NoError(t, err)
+		require.NoError(t, parsed.Verify(hmacKey))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		doc := []byte(`{"payload":"Zm9v","protected":"` + header + `","signature":"` + signature + `"}`)
+
+		_, err := jwscore.ParseJSON(doc)
+		require.ErrorIs(t, err, jwtcore.ErrUnsupportedCritical)
+
+		parsed, err := jwscore.ParseJSONWithConfig(doc, &jwscore.ParseConfig{
+			Critical: []string{"http://example.com/UNDEFINED"},
+		})
+		require.NoError(t, err)
+
+		_, err = parsed.Verify(func(_ *jwa.JWH) (any, error) { return hmacKey, nil })
+		require.NoError(t, err)
+	})
+
+	t.Run("JSON unprotected crit", func(t *testing.T) {
+		protected, err := jwtcore.Encode(jwa.JWH{Alg: jwa.HS256})
+		require.NoError(t, err)
+
+		doc := []byte(`{"payload":"Zm9v","protected":"` + protected + `","header":{"crit":["b64"]},"signature":"x"}`)
+
+		_, err = jwscore.ParseJSON(doc)
+		require.ErrorIs(t, err, jwtcore.ErrInvalidCritical)
+	})
+}
+
+func TestPrivateHeaderParameters(t *testing.T) {
+	hmacKey, err := jwkgen.HMAC(jwkgen.H256KeySize)
+	require.NoError(t, err)
+
+	header := jwa.JWH{
+		Alg: jwa.HS256,
+		Extra: map[string]json.RawMessage{
+			"tenant": json.RawMessage(`"tenant-1"`),
+		},
+	}
+
+	token, err := jwscore.Sign(header, []byte("foo"), hmacKey)
+	require.NoError(t, err)
+
+	parsed, err := jwscore.Parse(token)
+	require.NoError(t, err)
+	require.Equal(t, header, parsed.Header)
+	require.NoError(t, parsed.Verify(hmacKey))
+
+	// The decoded header is encoded back to the same protected header.
+	encoded, err := jwtcore.Encode(parsed.Header)
+	require.NoError(t, err)
+	require.Equal(t, parsed.Protected, encoded)
+}
+
+func TestParseLimits(t *testing.T) {
+	hmacKey, err := jwkgen.HMAC(jwkgen.H256KeySize)
+	require.NoError(t, err)
+
+	token, err := jwscore.Sign(jwa.JWH{Alg: jwa.HS256}, []byte(strings.Repeat("a", 100)), hmacKey)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		token  string
+		limits *jwtcore.Limits
+
+		expect error
+	}{
+		{
+			name:   "ok",
+			token:  token,
+			limits: &jwtcore.Limits{MaxTokenSize: len(token)},
+		},
+		{
+			name:   "token too large",
+			token:  token,
+			limits: &jwtcore.Limits{MaxTokenSize: len(token) - 1},
+			expect: jwtcore.ErrLimitExceeded,
+		},
+		{
+			name:   "header too large",
+			token:  token,
+			limits: &jwtcore.Limits{MaxHeaderSize: 10},
+			expect: jwtcore.ErrLimitExceeded,
+		},
+		{
+			name:   "payload too large",
+			token:  token,
+			limits: &jwtcore.Limits{MaxPayloadSize: 100},
+			expect: jwtcore.ErrLimitExceeded,
+		},
+		{
+			name:   "too many segments",
+			token:  token + strings.Repeat(".", 1000),
+			expect: jwtcore.ErrTooManySegments,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := jwscore.ParseWithConfig(testCase.token, &jwscore.ParseConfig{Limits: testCase.limits})
+			require.ErrorIs(t, err, testCase.expect)
+		})
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		doc := []byte(`{"payload":"` + strings.Repeat("a", 200) + `","signatures":[{"protected":"e30","signature":"x"}]}`)
+
+		_, err := jwscore.ParseJSONWithConfig(doc, &jwscore.ParseConfig{Limits: &jwtcore.Limits{MaxPayloadSize: 200}})
+		require.NoError(t, err)
+
+		_, err = jwscore.ParseJSONWithConfig(doc, &jwscore.ParseConfig{Limits: &jwtcore.Limits{MaxPayloadSize: 199}})
+		require.ErrorIs(t, err, jwtcore.ErrLimitExceeded)
+
+		_, err = jwscore.ParseJSONWithConfig(doc, &jwscore.ParseConfig{Limits: &jwtcore.Limits{MaxHeaderSize: 2}})
+		require.ErrorIs(t, err, jwtcore.ErrLimitExceeded)
+
+		_, err = jwscore.ParseJSONWithConfig(doc, &jwscore.ParseConfig{Limits: &jwtcore.Limits{MaxTokenSize: 100}})
+		require.ErrorIs(t, err, jwtcore.ErrLimitExceeded)
+	})
+}
@@ -0,0 +1,76 @@
+package jwscore
+
+import (
+	"fmt"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+)
+
+// SignDetached creates a new JWS in compact serialization, with detached content. The payload is signed, but
+// not included in the token, which takes the form "header..signature". It must be transmitted separately to the
+// recipient.
+//
+// The header and key follow the same rules as Sign. Since the payload is not part of the token, an unencoded
+// payload ("b64" header set to false) may contain '.' characters.
+//
+// https://datatracker.ietf.org/doc/html/rfc7515#appendix-F
+//
+// To create a JWS with detached content, the JWS is created as usual, and then the payload representation is
+// deleted from the JWS. The resulting JWS Compact Serialization is of the form
+// BASE64URL(UTF8(JWS Protected Header)) || '.' || '.' || BASE64URL(JWS Signature).
+func SignDetached(header jwa.JWH, payload []byte, key any) (string, error) {
+	encodedHeader, err := jwtcore.Encode(header)
+	if err != nil {
+		return "", fmt.Errorf("encode header: %w", err)
+	}
+
+	unsigned, err := SigningInput(&header, encodedHeader, payload)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := sign(header.Alg, unsigned, key)
+	if err != nil {
+		return "", fmt.Errorf("sign token: %w", err)
+	}
+
+	return jwtcore.Assemble(encodedHeader, "", signature), nil
+}
+
+// VerifyDetached parses a JWS with detached content, and verifies its signature against the externally supplied
+// payload. The returned JWS holds the detached payload.
+//
+// https://datatracker.ietf.org/doc/html/rfc7515#appendix-F
+//
+// To verify the JWS, the recipient reconstructs the JWS by re-inserting the payload representation into the
+// JWS, and then verifies it as usual.
+func VerifyDetached(token string, payload []byte, key any) (*JWS, error) {
+	return VerifyDetachedWithConfig(token, payload, key, nil)
+}
+
+// VerifyDetachedWithConfig works like VerifyDetached, with a custom configuration. A nil configuration uses the
+// defaults.
+func VerifyDetachedWithConfig(token string, payload []byte, key any, config *ParseConfig) (*JWS, error) {
+	parsed, err := ParseWithConfig(token, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parsed.Payload) > 0 {
+		return nil, fmt.Errorf("%w: detached token must have an empty payload segment", ErrMalformedToken)
+	}
+
+	if err = parsed.VerifyPayload(payload, key); err != nil {
+		return nil, err
+	}
+
+	// The signing input of the token is rebuilt with the detached payload, as it was signed.
+	if parsed.SigningInput, err = SigningInput(&parsed.Header, parsed.Protected, payload); err != nil {
+		return nil, err
+	}
+
+	parsed.Payload = payload
+
+	return parsed, nil
+}
@@ -0,0 +1,140 @@
+package jwscore_test
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+	jwscore "github.com/a-novel-kit/jwt-core/jws"
+)
+
+func TestSignAndVerifyDetached(t *testing.T) {
+	ecKey, err := jwkgen.EC(elliptic.P256())
+	require.NoError(t, err)
+
+	hmacKey, err := jwkgen.HMAC(jwkgen.H256KeySize)
+	require.NoError(t, err)
+
+	b64 := false
+
+	testCases := []struct {
+		name string
+
+		header    jwa.JWH
+		signKey   any
+		verifyKey any
+	}{
+		{
+			name:      "encoded",
+			header:    jwa.JWH{Alg: jwa.ES256},
+			signKey:   ecKey,
+			verifyKey: &ecKey.PublicKey,
+		},
+		{
+			name:      "unencoded",
+			header:    jwa.JWH{Alg: jwa.HS256, B64: &b64, Crit: []string{"b64"}},
+			signKey:   hmacKey,
+			verifyKey: hmacKey,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			body := []byte(`{"amount":"10.00"}`)
+
+			token, err := jwscore.SignDetached(testCase.header, body, testCase.signKey)
+			require.NoError(t, err)
+
+			parts := strings.Split(token, ".")
+			require.Len(t, parts, 3)
+			require.Empty(t, parts[1])
+
+			parsed, err := jwscore.VerifyDetached(token, body, testCase.verifyKey)
+			require.NoError(t, err)
+			require.Equal(t, body, parsed.Payload)
+			require.NoError(t, parsed.Verify(testCase.verifyKey))
+
+			_, err = jwscore.VerifyDetached(token, []byte(`{"amount":"1000.00"}`), testCase.verifyKey)
+			require.ErrorIs(t, err, jwscore.ErrInvalidSignature)
+		})
+	}
+}
+
+func TestVerifyDetached(t *testing.T) {
+	hmacKey, err := jwkgen.HMAC(jwkgen.H256KeySize)
+	require.NoError(t, err)
+
+	body := []byte("body")
+
+	attached, err := jwscore.Sign(jwa.JWH{Alg: jwa.HS256}, body, hmacKey)
+	require.NoError(t, err)
+
+	detached, err := jwscore.SignDetached(jwa.JWH{Alg: jwa.HS256}, body, hmacKey)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		token string
+
+		expect error
+	}{
+		{
+			name:   "attached payload",
+			token:  attached,
+			expect: jwscore.ErrMalformedToken,
+		},
+		{
+			name:   "missing signature",
+			token:  strings.TrimRight(detached, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_"),
+			expect: jwscore.ErrMissingSignature,
+		},
+		{
+			name:   "malformed token",
+			token:  "foo",
+			expect: jwscore.ErrMalformedToken,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := jwscore.VerifyDetached(testCase.token, body, hmacKey)
+			require.ErrorIs(t, err, testCase.expect)
+		})
+	}
+}
+
+func TestVerifyDetachedWithConfig(t *testing.T) {
+	hmacKey, err := jwkgen.HMAC(jwkgen.H256KeySize)
+	require.NoError(t, err)
+
+	body := []byte("body")
+
+	header := jwa.JWH{
+		Alg:   jwa.HS256,
+		Crit:  []string{"exp"},
+		Extra: map[string]json.RawMessage{"exp": json.RawMessage("1363284000")},
+	}
+
+	token, err := jwscore.SignDetached(header, body, hmacKey)
+	require.NoError(t, err)
+
+	_, err = jwscore.VerifyDetached(token, body, hmacKey)
+	require.ErrorIs(t, err, jwtcore.ErrUnsupportedCritical)
+
+	parsed, err := jwscore.VerifyDetachedWithConfig(token, body, hmacKey, &jwscore.ParseConfig{Critical: []string{"exp"}})
+	require.NoError(t, err)
+	require.Equal(t, body, parsed.Payload)
+
+	_, err = jwscore.VerifyDetachedWithConfig(token, body, hmacKey, &jwscore.ParseConfig{
+		Critical: []string{"exp"},
+		Limits:   &jwtcore.Limits{MaxTokenSize: 10},
+	})
+	require.ErrorIs(t, err, jwtcore.ErrLimitExceeded)
+}
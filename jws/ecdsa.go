@@ -5,6 +5,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"encoding/asn1"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -24,9 +25,16 @@ func inferECDSAKeySize(params *elliptic.CurveParams) int {
 }
 
 // SignEC signs the payload using the Elliptic Curve algorithm.
-func SignEC(unsigned string, key *ecdsa.PrivateKey) (string, error) {
+//
+// The key can be any crypto.Signer backed by an ECDSA key, such as a *ecdsa.PrivateKey or a key stored in a KMS.
+func SignEC(unsigned string, key crypto.Signer) (string, error) {
+	publicKey, ok := key.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return "", fmt.Errorf("%w: expected an ECDSA key, got %T", ErrInvalidKey, key.Public())
+	}
+
 	var hash crypto.Hash
-	switch key.Curve.Params().Name {
+	switch publicKey.Curve.Params().Name {
 	case "P-256":
 		hash = crypto.SHA256
 	case "P-384":
@@ -40,12 +48,29 @@ func SignEC(unsigned string, key *ecdsa.PrivateKey) (string, error) {
 	hasher := hash.New()
 	hasher.Write([]byte(unsigned))
 
-	r, s, err := ecdsa.Sign(rand.Reader, key, hasher.Sum(nil)) //nolint:varnamelen
+	// crypto.Signer implementations return ASN.1 DER encoded signatures, that must be converted to the raw
+	// representation expected by JWS.
+	der, err := key.Sign(rand.Reader, hasher.Sum(nil), hash)
 	if err != nil {
 		return "", fmt.Errorf("sign payload: %w", err)
 	}
 
-	keyBytes := inferECDSAKeySize(key.Curve.Params())
+	var sig struct {
+		R, S *big.Int
+	}
+
+	rest, err := asn1.Unmarshal(der, &sig)
+	if err != nil {
+		return "", fmt.Errorf("decode signature: %w", err)
+	}
+
+	r, s := sig.R, sig.S //nolint:varnamelen
+
+	keyBytes := inferECDSAKeySize(publicKey.Curve.Params())
+
+	if len(rest) > 0 || r.Sign() <= 0 || s.Sign() <= 0 || r.BitLen() > 8*keyBytes || s.BitLen() > 8*keyBytes {
+		return "", errors.New("decode signature: invalid ASN.1 signature")
+	}
 
 	// We serialize the outputs (r and s) into big-endian byte arrays
 	// padded with zeros on the left to make sure the sizes work out.
@@ -72,7 +97,7 @@ func VerifyEC(unsigned string, signature string, key *ecdsa.PublicKey) error {
 	}
 
 	if signature == "" {
-		return nil
+		return ErrMissingSignature
 	}
 
 	sigBytes, err := base64.RawURLEncoding.DecodeString(signature)
@@ -82,7 +107,7 @@ func VerifyEC(unsigned string, signature string, key *ecdsa.PublicKey) error {
 
 	keyBytes := inferECDSAKeySize(key.Curve.Params())
 	if len(sigBytes) != 2*keyBytes {
-		return nil
+		return fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidSignature, 2*keyBytes, len(sigBytes))
 	}
 
 	r := big.NewInt(0).SetBytes(sigBytes[:keyBytes]) //nolint:varnamelen
@@ -73,4 +73,21 @@ func TestSignAndVerifyEC(t *testing.T) {
 		err = jwscore.VerifyEC(strToSign, "&/?.,<>", &key.PublicKey)
 		require.Error(t, err)
 	})
+
+	t.Run("WrongSignatureLength", func(t *testing.T) {
+		key, err := jwkgen.EC(elliptic.P256())
+		require.NoError(t, err)
+		require.NotEmpty(t, key)
+
+		strToSign := "Hello, World!"
+
+		// Sign the string.
+		signature, err := jwscore.SignEC(strToSign, key)
+		require.NoError(t, err)
+		require.NotEmpty(t, signature)
+
+		// Truncate the signature.
+		err = jwscore.VerifyEC(strToSign, signature[:len(signature)/2], &key.PublicKey)
+		require.ErrorIs(t, err, jwscore.ErrInvalidSignature)
+	})
 }
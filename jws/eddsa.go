@@ -14,6 +14,10 @@ func SignED25519(unsigned string, key ed25519.PrivateKey) string {
 
 // VerifyED25519 verifies the signature of the payload using the EdDSA algorithm with Ed25519 curve.
 func VerifyED25519(unsigned string, signature string, key ed25519.PublicKey) error {
+	if signature == "" {
+		return ErrMissingSignature
+	}
+
 	sig, err := base64.RawURLEncoding.DecodeString(signature)
 	if err != nil {
 		return fmt.Errorf("decode signature: %w", err)
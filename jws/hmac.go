@@ -25,7 +25,7 @@ func VerifyHMAC(unsigned string, signature string, key []byte, hash crypto.Hash)
 	}
 
 	if signature == "" {
-		return nil
+		return ErrMissingSignature
 	}
 
 	sigBytes, err := base64.RawURLEncoding.DecodeString(signature)
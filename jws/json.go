@@ -0,0 +1,331 @@
+package jwscore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+)
+
+// JSONSignature is a single signature of a JWS in JSON serialization.
+//
+// https://datatracker.ietf.org/doc/html/rfc7515#section-7.2.1
+type JSONSignature struct {
+	// Protected is the base64url-encoded JWS Protected Header, integrity protected by the signature.
+	Protected string `json:"protected,omitempty"`
+	// Header is the JWS Unprotected Header.
+	Header *jwa.JWH `json:"header,omitempty"`
+	// Signature is the base64url-encoded signature.
+	Signature string `json:"signature"`
+}
+
+// JSON is a JWS in JSON serialization. It can hold multiple signatures over the same payload.
+//
+// https://datatracker.ietf.org/doc/html/rfc7515#section-7.2
+type JSON struct {
+	// Payload is the base64url-encoded payload. If the "b64" header is false, the payload is not encoded.
+	Payload string `json:"payload"`
+	// Signatures of the payload.
+	Signatures []JSONSignature `json:"signatures"`
+}
+
+// JSONSigner describes one of the signatures of a JWS in JSON serialization.
+type JSONSigner struct {
+	// Protected is the header protected by the signature.
+	Protected *jwa.JWH
+	// Header is the unprotected header of the signature. Its parameters must not overlap with the protected
+	// header.
+	Header *jwa.JWH
+	// Key used to compute the signature. Its type must match the algorithm, as described in Sign.
+	Key any
+}
+
+// JSONVerification is the result of the verification of a single signature.
+type JSONVerification struct {
+	// Header is the union of the protected and unprotected headers of the signature.
+	Header jwa.JWH
+	// Err is nil when the signature is valid.
+	Err error
+}
+
+// SignJSON creates a new JWS in JSON serialization, with one signature per signer. The algorithm of each signature
+// is read from the "alg" parameter of its headers.
+//
+// The "b64" parameter, if any, must be set in the protected header, with the same value for every signer.
+func SignJSON(payload []byte, signers ...JSONSigner) (*JSON, error) {
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("%w: at least one signer is required", ErrMissingSignature)
+	}
+
+	output := &JSON{Signatures: make([]JSONSignature, len(signers))}
+
+	var encoded bool
+
+	for i, signer := range signers {
+		var header jwa.JWH
+		if err := jwtcore.MergeHeaders(&header, signer.Protected, signer.Header); err != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, err)
+		}
+
+		if err := checkUnprotectedB64(signer.Header); err != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, err)
+		}
+
+		signerEncoded, err := IsPayloadEncoded(&header)
+		if err != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, err)
+		}
+
+		// https://datatracker.ietf.org/doc/html/rfc7797#section-3
+		//
+		// The "b64" Header Parameter value MUST be the same for all
+		// signatures in the JWS JSON Serialization.
+		if i == 0 {
+			encoded = signerEncoded
+			if output.Payload, err = encodePayload(&header, payload); err != nil {
+				return nil, fmt.Errorf("signature %d: %w", i, err)
+			}
+		} else if signerEncoded != encoded {
+			return nil, fmt.Errorf("%w: b64 must be the same for all signatures", ErrInvalidHeader)
+		}
+
+		var protected string
+		if signer.Protected != nil {
+			if protected, err = jwtcore.Encode(signer.Protected); err != nil {
+				return nil, fmt.Errorf("signature %d: encode protected header: %w", i, err)
+			}
+		}
+
+		signature, err := sign(header.Alg, jwtcore.Assemble(protected, output.Payload), signer.Key)
+		if err != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, err)
+		}
+
+		output.Signatures[i] = JSONSignature{
+			Protected: protected,
+			Header:    signer.Header,
+			Signature: signature,
+		}
+	}
+
+	return output, nil
+}
+
+// ParseJSON reads a JWS in either the general or the flattened JSON serialization.
+//
+// https://datatracker.ietf.org/doc/html/rfc7515#section-7.2.2
+func ParseJSON(data []byte) (*JSON, error) {
+	return ParseJSONWithConfig(data, nil)
+}
+
+// ParseJSONWithConfig works like ParseJSON, with a custom configuration. A nil configuration uses the defaults.
+//
+// The "crit" header of every signature is checked: the document is rejected if any of them lists an unsupported
+// extension.
+func ParseJSONWithConfig(data []byte, config *ParseConfig) (*JSON, error) {
+	limits := config.limits()
+
+	if err := limits.CheckTokenSize(len(data)); err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		Payload    *string         `json:"payload"`
+		Signatures []JSONSignature `json:"signatures"`
+
+		// Members of the flattened serialization.
+		JSONSignature
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	if raw.Payload == nil {
+		return nil, fmt.Errorf("%w: missing payload", ErrMalformedToken)
+	}
+
+	flattened := raw.Protected != "" || raw.Header != nil || raw.Signature != ""
+
+	if raw.Signatures != nil && flattened {
+		return nil, fmt.Errorf("%w: signatures must not be mixed with a flattened signature", ErrMalformedToken)
+	}
+
+	output := &JSON{Payload: *raw.Payload, Signatures: raw.Signatures}
+	if flattened {
+		output.Signatures = []JSONSignature{raw.JSONSignature}
+	}
+
+	if len(output.Signatures) == 0 {
+		return nil, fmt.Errorf("%w: missing signatures", ErrMalformedToken)
+	}
+
+	if err := limits.CheckPayloadSize(len(output.Payload)); err != nil {
+		return nil, err
+	}
+
+	for i, signature := range output.Signatures {
+		if err := limits.CheckHeaderSize(len(signature.Protected)); err != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, err)
+		}
+
+		if err := checkSignatureCritical(&signature, config); err != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, err)
+		}
+	}
+
+	return output, nil
+}
+
+// MarshalFlattened returns the flattened JSON serialization of the JWS. It only works for a JWS with a single
+// signature.
+//
+// https://datatracker.ietf.org/doc/html/rfc7515#section-7.2.2
+func (doc *JSON) MarshalFlattened() ([]byte, error) {
+	if len(doc.Signatures) != 1 {
+		return nil, fmt.Errorf(
+			"%w: flattened serialization requires exactly 1 signature, got %d", ErrMalformedToken, len(doc.Signatures),
+		)
+	}
+
+	return json.Marshal(struct {
+		Payload string `json:"payload"`
+		JSONSignature
+	}{
+		Payload:       doc.Payload,
+		JSONSignature: doc.Signatures[0],
+	})
+}
+
+// DecodePayload returns the decoded payload of the JWS. The payload is returned as is if the "b64" parameter of
+// the protected headers is false.
+func (doc *JSON) DecodePayload() ([]byte, error) {
+	for _, signature := range doc.Signatures {
+		if signature.Protected == "" {
+			continue
+		}
+
+		var protected jwa.JWH
+		if err := jwtcore.DecodeStrict(signature.Protected, &protected); err != nil {
+			return nil, fmt.Errorf("%w: decode protected header: %w", ErrMalformedToken, err)
+		}
+
+		if protected.B64 != nil && !*protected.B64 {
+			return []byte(doc.Payload), nil
+		}
+	}
+
+	payload, err := jwtcore.DecodeSegment(doc.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: decode payload: %w", ErrMalformedToken, err)
+	}
+
+	return payload, nil
+}
+
+// Verify checks every signature of the JWS. The key for each signature is retrieved using keyFunc, from the
+// header of the signature.
+//
+// The returned slice holds the result for each signature, in order. An error is returned when none of the
+// signatures is valid.
+func (doc *JSON) Verify(keyFunc func(header *jwa.JWH) (any, error)) ([]JSONVerification, error) {
+	results := make([]JSONVerification, len(doc.Signatures))
+	errs := []error{ErrInvalidSignature}
+	valid := false
+
+	for i, signature := range doc.Signatures {
+		results[i].Err = doc.verifySignature(&signature, &results[i].Header, keyFunc)
+		if results[i].Err == nil {
+			valid = true
+
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf("signature %d: %w", i, results[i].Err))
+	}
+
+	if !valid {
+		return results, errors.Join(errs...)
+	}
+
+	return results, nil
+}
+
+func (doc *JSON) verifySignature(
+	signature *JSONSignature, header *jwa.JWH, keyFunc func(header *jwa.JWH) (any, error),
+) error {
+	var protected *jwa.JWH
+	if signature.Protected != "" {
+		protected = new(jwa.JWH)
+		if err := jwtcore.DecodeStrict(signature.Protected, protected); err != nil {
+			return fmt.Errorf("%w: decode protected header: %w", ErrMalformedToken, err)
+		}
+	}
+
+	if err := jwtcore.MergeHeaders(header, protected, signature.Header); err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	if err := checkUnprotectedB64(signature.Header); err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	if _, err := IsPayloadEncoded(header); err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	if signature.Signature == "" {
+		return ErrMissingSignature
+	}
+
+	key, err := keyFunc(header)
+	if err != nil {
+		return fmt.Errorf("retrieve key: %w", err)
+	}
+
+	// The payload is used as it appears in the document, whether it is encoded or not.
+	return verify(header.Alg, jwtcore.Assemble(signature.Protected, doc.Payload), signature.Signature, key)
+}
+
+// checkUnprotectedB64 makes sure the "b64" parameter is not set in an unprotected header. Like "crit", it must
+// be integrity protected.
+//
+// https://datatracker.ietf.org/doc/html/rfc7797#section-3
+func checkUnprotectedB64(header *jwa.JWH) error {
+	if header != nil && header.B64 != nil {
+		return fmt.Errorf("%w: b64 must be set in the protected header", ErrInvalidHeader)
+	}
+
+	return nil
+}
+
+// checkSignatureCritical validates the "crit" header of a signature. When used, "crit" must be integrity
+// protected.
+//
+// https://datatracker.ietf.org/doc/html/rfc7515#section-4.1.11
+//
+// When used, this Header Parameter MUST be integrity protected; therefore, it MUST occur only within the JWS
+// Protected Header.
+func checkSignatureCritical(signature *JSONSignature, config *ParseConfig) error {
+	if signature.Header != nil && signature.Header.Crit != nil {
+		return fmt.Errorf("%w: crit must be set in the protected header", jwtcore.ErrInvalidCritical)
+	}
+
+	if signature.Protected == "" {
+		return nil
+	}
+
+	err := jwtcore.CheckCritical(signature.Protected, config.critical())
+	if errors.Is(err, jwtcore.ErrInvalidCritical) || errors.Is(err, jwtcore.ErrUnsupportedCritical) {
+		return fmt.Errorf("check critical header: %w", err)
+	}
+
+	// Any other error comes from the decoding of the protected header.
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	return nil
+}
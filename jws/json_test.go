@@ -0,0 +1,245 @@
+package jwscore_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+	jwscore "github.com/a-novel-kit/jwt-core/jws"
+)
+
+func TestSignJSONAndVerify(t *testing.T) {
+	rsaKey, err := jwkgen.RSA(jwkgen.RS256KeySize)
+	require.NoError(t, err)
+
+	edPrivKey, edPubKey, err := jwkgen.ED25519()
+	require.NoError(t, err)
+
+	payload := []byte(`{"iss":"joe"}`)
+
+	keys := map[string]any{
+		"rsa": &rsaKey.PublicKey,
+		"ed":  edPubKey,
+	}
+
+	keyFunc := func(header *jwa.JWH) (any, error) {
+		key, ok := keys[header.KID]
+		if !ok {
+			return nil, errors.New("unknown key")
+		}
+
+		return key, nil
+	}
+
+	doc, err := jwscore.SignJSON(
+		payload,
+		jwscore.JSONSigner{
+			Protected: &jwa.JWH{Alg: jwa.PS256},
+			Header:    &jwa.JWH{KID: "rsa"},
+			Key:       rsaKey,
+		},
+		jwscore.JSONSigner{
+			Protected: &jwa.JWH{Alg: jwa.EdDSA, KID: "ed"},
+			Key:       edPrivKey,
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, doc.Signatures, 2)
+
+	t.Run("general", func(t *testing.T) {
+		serialized, err := json.Marshal(doc)
+		require.NoError(t, err)
+
+		parsed, err := jwscore.ParseJSON(serialized)
+		require.NoError(t, err)
+
+		results, err := parsed.Verify(keyFunc)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		require.NoError(t, results[0].Err)
+		require.Equal(t, jwa.JWH{Alg: jwa.PS256, KID: "rsa"}, results[0].Header)
+		require.NoError(t, results[1].Err)
+		require.Equal(t, jwa.JWH{Alg: jwa.EdDSA, KID: "ed"}, results[1].Header)
+
+		decoded, err := parsed.DecodePayload()
+		require.NoError(t, err)
+		require.Equal(t, payload, decoded)
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		parsed := *doc
+		parsed.Signatures = append([]jwscore.JSONSignature{}, doc.Signatures...)
+		parsed.Signatures[0].Signature = doc.Signatures[1].Signature
+
+		results, err := parsed.Verify(keyFunc)
+		require.NoError(t, err)
+		require.ErrorIs(t, results[0].Err, jwscore.ErrInvalidSignature)
+		require.NoError(t, results[1].Err)
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		parsed := *doc
+		parsed.Payload = "eyJpc3MiOiJqYW5lIn0"
+
+		results, err := parsed.Verify(keyFunc)
+		require.ErrorIs(t, err, jwscore.ErrInvalidSignature)
+		require.Error(t, results[0].Err)
+		require.Error(t, results[1].Err)
+	})
+
+	t.Run("flattened", func(t *testing.T) {
+		_, err := doc.MarshalFlattened()
+		require.ErrorIs(t, err, jwscore.ErrMalformedToken)
+
+		single := jwscore.JSON{Payload: doc.Payload, Signatures: doc.Signatures[1:]}
+
+		serialized, err := single.MarshalFlattened()
+		require.NoError(t, err)
+		require.NotContains(t, string(serialized), `"signatures"`)
+
+		parsed, err := jwscore.ParseJSON(serialized)
+		require.NoError(t, err)
+		require.Equal(t, single, *parsed)
+
+		results, err := parsed.Verify(keyFunc)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.NoError(t, results[0].Err)
+	})
+}
+
+func TestSignJSON(t *testing.T) {
+	hmacKey, err := jwkgen.HMAC(jwkgen.H256KeySize)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		signers []jwscore.JSONSigner
+
+		expectErr error
+	}{
+		{
+			name: "no signers",
+
+			expectErr: jwscore.ErrMissingSignature,
+		},
+		{
+			name: "duplicate header parameter",
+
+			signers: []jwscore.JSONSigner{
+				{
+					Protected: &jwa.JWH{Alg: jwa.HS256},
+					Header:    &jwa.JWH{Alg: jwa.HS256},
+					Key:       hmacKey,
+				},
+			},
+
+			expectErr: jwtcore.ErrDuplicateHeader,
+		},
+		{
+			name: "unsupported algorithm",
+
+			signers: []jwscore.JSONSigner{
+				{
+					Header: &jwa.JWH{KID: "key-1"},
+					Key:    hmacKey,
+				},
+			},
+
+			expectErr: jwscore.ErrUnsupportedAlg,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := jwscore.SignJSON([]byte("payload"), testCase.signers...)
+			require.ErrorIs(t, err, testCase.expectErr)
+		})
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		data string
+
+		expect    *jwscore.JSON
+		expectErr error
+	}{
+		{
+			name: "general",
+
+			data: `{"payload":"cGF5bG9hZA","signatures":[{"protected":"eyJhbGciOiJIUzI1NiJ9","signature":"c2ln"}]}`,
+
+			expect: &jwscore.JSON{
+				Payload: "cGF5bG9hZA",
+				Signatures: []jwscore.JSONSignature{
+					{Protected: "eyJhbGciOiJIUzI1NiJ9", Signature: "c2ln"},
+				},
+			},
+		},
+		{
+			name: "flattened",
+
+			data: `{"payload":"cGF5bG9hZA","header":{"kid":"key-1"},"signature":"c2ln"}`,
+
+			expect: &jwscore.JSON{
+				Payload: "cGF5bG9hZA",
+				Signatures: []jwscore.JSONSignature{
+					{Header: &jwa.JWH{KID: "key-1"}, Signature: "c2ln"},
+				},
+			},
+		},
+		{
+			name: "missing payload",
+
+			data: `{"signatures":[{"signature":"c2ln"}]}`,
+
+			expectErr: jwscore.ErrMalformedToken,
+		},
+		{
+			name: "missing signatures",
+
+			data: `{"payload":"cGF5bG9hZA"}`,
+
+			expectErr: jwscore.ErrMalformedToken,
+		},
+		{
+			name: "mixed serializations",
+
+			data: `{"payload":"cGF5bG9hZA","signatures":[{"signature":"c2ln"}],"signature":"c2ln"}`,
+
+			expectErr: jwscore.ErrMalformedToken,
+		},
+		{
+			name: "invalid protected header",
+
+			data: `{"payload":"cGF5bG9hZA","protected":"Zm9v","signature":"c2ln"}`,
+
+			expectErr: jwscore.ErrMalformedToken,
+		},
+		{
+			name: "invalid JSON",
+
+			data: `{"payload":`,
+
+			expectErr: jwscore.ErrMalformedToken,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			parsed, err := jwscore.ParseJSON([]byte(testCase.data))
+			require.ErrorIs(t, err, testCase.expectErr)
+			require.Equal(t, testCase.expect, parsed)
+		})
+	}
+}
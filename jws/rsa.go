@@ -11,19 +11,26 @@ import (
 
 // SignRSA signs a string using RSA PKCS1 v1.5 and returns the signature.
 //
+// The key can be any crypto.Signer backed by an RSA key, such as a *rsa.PrivateKey or a key stored in a KMS.
+//
 // Deprecated: RSASSA PKCS #1 v1.5 has been deprecated by the standards, and is only included for
 // backwards compatibility. Use SignRSAPSS instead.
 //
 // https://www.rfc-editor.org/rfc/rfc8017#section-8
-func SignRSA(unsigned string, key *rsa.PrivateKey, hash crypto.Hash) (string, error) {
+func SignRSA(unsigned string, key crypto.Signer, hash crypto.Hash) (string, error) {
 	if !hash.Available() {
 		return "", ErrHashUnavailable
 	}
 
+	if _, ok := key.Public().(*rsa.PublicKey); !ok {
+		return "", fmt.Errorf("%w: expected an RSA key, got %T", ErrInvalidKey, key.Public())
+	}
+
 	hasher := hash.New()
 	hasher.Write([]byte(unsigned))
 
-	sigBytes, err := rsa.SignPKCS1v15(rand.Reader, key, hash, hasher.Sum(nil))
+	// Passing the hash as the signer options selects PKCS1 v1.5 signatures.
+	sigBytes, err := key.Sign(rand.Reader, hasher.Sum(nil), hash)
 	if err != nil {
 		return "", fmt.Errorf("rsa.SignPKCS1v15: %w", err)
 	}
@@ -43,7 +50,7 @@ func VerifyRSA(unsigned string, signature string, key *rsa.PublicKey, hash crypt
 	}
 
 	if signature == "" {
-		return nil
+		return ErrMissingSignature
 	}
 
 	hasher := hash.New()
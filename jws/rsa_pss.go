@@ -10,16 +10,26 @@ import (
 )
 
 // SignRSAPSS signs the payload using the RSA-PSS algorithm.
-func SignRSAPSS(unsigned string, key *rsa.PrivateKey, hash crypto.Hash) (string, error) {
+//
+// The key can be any crypto.Signer backed by an RSA key, such as a *rsa.PrivateKey or a key stored in a KMS.
+func SignRSAPSS(unsigned string, key crypto.Signer, hash crypto.Hash) (string, error) {
 	if !hash.Available() {
 		return "", ErrHashUnavailable
 	}
 
+	if _, ok := key.Public().(*rsa.PublicKey); !ok {
+		return "", fmt.Errorf("%w: expected an RSA key, got %T", ErrInvalidKey, key.Public())
+	}
+
 	hasher := hash.New()
 	hasher.Write([]byte(unsigned))
 
-	sigBytes, err := rsa.SignPSS(rand.Reader, key, hash, hasher.Sum(nil), &rsa.PSSOptions{
+	// https://datatracker.ietf.org/doc/html/rfc7518#section-3.5
+	//
+	// The size of the salt value is the same size as the hash function output.
+	sigBytes, err := key.Sign(rand.Reader, hasher.Sum(nil), &rsa.PSSOptions{
 		SaltLength: rsa.PSSSaltLengthEqualsHash,
+		Hash:       hash,
 	})
 	if err != nil {
 		return "", fmt.Errorf("rsa.SignPSS: %w", err)
@@ -35,7 +45,7 @@ func VerifyRSAPSS(unsigned string, signature string, key *rsa.PublicKey, hash cr
 	}
 
 	if signature == "" {
-		return nil
+		return ErrMissingSignature
 	}
 
 	hasher := hash.New()
@@ -0,0 +1,288 @@
+package jwscore
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+)
+
+// Signer computes signatures using a specific algorithm and private key.
+type Signer interface {
+	// Alg returns the algorithm of the signer, as it appears in the "alg" header.
+	Alg() jwa.Alg
+	// Sign returns the base64url-encoded signature of a JWS Signing Input.
+	Sign(unsigned string) (string, error)
+}
+
+// Verifier checks signatures using a specific algorithm and public key.
+type Verifier interface {
+	// Alg returns the algorithm of the verifier, as it appears in the "alg" header.
+	Alg() jwa.Alg
+	// Verify checks a base64url-encoded signature against a JWS Signing Input. It returns ErrInvalidSignature if
+	// the signature does not match.
+	Verify(unsigned, signature string) error
+}
+
+// signerConstructor creates a Signer for an algorithm. It returns ErrInvalidKey if the key does not match the
+// algorithm.
+type signerConstructor func(alg jwa.Alg, key any) (Signer, error)
+
+// verifierConstructor creates a Verifier for an algorithm. It returns ErrInvalidKey if the key does not match the
+// algorithm.
+type verifierConstructor func(alg jwa.Alg, key any) (Verifier, error)
+
+var signerConstructors = map[jwa.Alg]signerConstructor{
+	jwa.HS256: newHMACSigner,
+	jwa.HS384: newHMACSigner,
+	jwa.HS512: newHMACSigner,
+	jwa.RS256: newRSASigner,
+	jwa.RS384: newRSASigner,
+	jwa.RS512: newRSASigner,
+	jwa.PS256: newRSAPSSSigner,
+	jwa.PS384: newRSAPSSSigner,
+	jwa.PS512: newRSAPSSSigner,
+	jwa.ES256: newECSigner,
+	jwa.ES384: newECSigner,
+	jwa.ES512: newECSigner,
+	jwa.EdDSA: newED25519Signer,
+}
+
+var verifierConstructors = map[jwa.Alg]verifierConstructor{
+	jwa.HS256: newHMACVerifier,
+	jwa.HS384: newHMACVerifier,
+	jwa.HS512: newHMACVerifier,
+	jwa.RS256: newRSAVerifier,
+	jwa.RS384: newRSAVerifier,
+	jwa.RS512: newRSAVerifier,
+	jwa.PS256: newRSAPSSVerifier,
+	jwa.PS384: newRSAPSSVerifier,
+	jwa.PS512: newRSAPSSVerifier,
+	jwa.ES256: newECVerifier,
+	jwa.ES384: newECVerifier,
+	jwa.ES512: newECVerifier,
+	jwa.EdDSA: newED25519Verifier,
+}
+
+// NewSigner creates a Signer for the given algorithm. The key must match the type expected by the algorithm:
+//
+//   - HS256, HS384, HS512: []byte
+//   - RS256, RS384, RS512, PS256, PS384, PS512: *rsa.PrivateKey, or a crypto.Signer backed by an RSA key
+//   - ES256, ES384, ES512: *ecdsa.PrivateKey, or a crypto.Signer backed by an ECDSA key
+//   - EdDSA: ed25519.PrivateKey
+func NewSigner(alg jwa.Alg, key any) (Signer, error) {
+	constructor, ok := signerConstructors[alg]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlg, alg)
+	}
+
+	return constructor(alg, key)
+}
+
+// NewVerifier creates a Verifier for the given algorithm. The key must be the public counterpart of the one
+// expected by NewSigner (or the same secret, for HMAC).
+func NewVerifier(alg jwa.Alg, key any) (Verifier, error) {
+	constructor, ok := verifierConstructors[alg]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlg, alg)
+	}
+
+	return constructor(alg, key)
+}
+
+type signer struct {
+	alg  jwa.Alg
+	sign func(unsigned string) (string, error)
+}
+
+func (s *signer) Alg() jwa.Alg {
+	return s.alg
+}
+
+func (s *signer) Sign(unsigned string) (string, error) {
+	return s.sign(unsigned)
+}
+
+type verifier struct {
+	alg    jwa.Alg
+	verify func(unsigned, signature string) error
+}
+
+func (v *verifier) Alg() jwa.Alg {
+	return v.alg
+}
+
+func (v *verifier) Verify(unsigned, signature string) error {
+	return v.verify(unsigned, signature)
+}
+
+func newHMACSigner(alg jwa.Alg, key any) (Signer, error) {
+	hmacKey, ok := key.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s expects []byte, got %T", ErrInvalidKey, alg, key)
+	}
+
+	return &signer{alg: alg, sign: func(unsigned string) (string, error) {
+		return SignHMAC(unsigned, hmacKey, algHash(alg))
+	}}, nil
+}
+
+func newHMACVerifier(alg jwa.Alg, key any) (Verifier, error) {
+	hmacKey, ok := key.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s expects []byte, got %T", ErrInvalidKey, alg, key)
+	}
+
+	return &verifier{alg: alg, verify: func(unsigned, signature string) error {
+		return VerifyHMAC(unsigned, signature, hmacKey, algHash(alg))
+	}}, nil
+}
+
+func newRSASigner(alg jwa.Alg, key any) (Signer, error) {
+	rsaKey, err := rsaSignerKey(alg, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &signer{alg: alg, sign: func(unsigned string) (string, error) {
+		return SignRSA(unsigned, rsaKey, algHash(alg)) //nolint:staticcheck
+	}}, nil
+}
+
+func newRSAVerifier(alg jwa.Alg, key any) (Verifier, error) {
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s expects *rsa.PublicKey, got %T", ErrInvalidKey, alg, key)
+	}
+
+	return &verifier{alg: alg, verify: func(unsigned, signature string) error {
+		return VerifyRSA(unsigned, signature, rsaKey, algHash(alg)) //nolint:staticcheck
+	}}, nil
+}
+
+func newRSAPSSSigner(alg jwa.Alg, key any) (Signer, error) {
+	rsaKey, err := rsaSignerKey(alg, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &signer{alg: alg, sign: func(unsigned string) (string, error) {
+		return SignRSAPSS(unsigned, rsaKey, algHash(alg))
+	}}, nil
+}
+
+func newRSAPSSVerifier(alg jwa.Alg, key any) (Verifier, error) {
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s expects *rsa.PublicKey, got %T", ErrInvalidKey, alg, key)
+	}
+
+	return &verifier{alg: alg, verify: func(unsigned, signature string) error {
+		return VerifyRSAPSS(unsigned, signature, rsaKey, algHash(alg))
+	}}, nil
+}
+
+func newECSigner(alg jwa.Alg, key any) (Signer, error) {
+	ecKey, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s expects *ecdsa.PrivateKey or crypto.Signer, got %T", ErrInvalidKey, alg, key)
+	}
+
+	publicKey, ok := ecKey.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s expects an ECDSA key, got %T", ErrInvalidKey, alg, ecKey.Public())
+	}
+
+	if err := checkECCurve(alg, publicKey.Curve.Params().Name); err != nil {
+		return nil, err
+	}
+
+	return &signer{alg: alg, sign: func(unsigned string) (string, error) {
+		return SignEC(unsigned, ecKey)
+	}}, nil
+}
+
+func newECVerifier(alg jwa.Alg, key any) (Verifier, error) {
+	ecKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s expects *ecdsa.PublicKey, got %T", ErrInvalidKey, alg, key)
+	}
+
+	if err := checkECCurve(alg, ecKey.Curve.Params().Name); err != nil {
+		return nil, err
+	}
+
+	return &verifier{alg: alg, verify: func(unsigned, signature string) error {
+		return VerifyEC(unsigned, signature, ecKey)
+	}}, nil
+}
+
+func newED25519Signer(alg jwa.Alg, key any) (Signer, error) {
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s expects ed25519.PrivateKey, got %T", ErrInvalidKey, alg, key)
+	}
+
+	return &signer{alg: alg, sign: func(unsigned string) (string, error) {
+		return SignED25519(unsigned, edKey), nil
+	}}, nil
+}
+
+func newED25519Verifier(alg jwa.Alg, key any) (Verifier, error) {
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s expects ed25519.PublicKey, got %T", ErrInvalidKey, alg, key)
+	}
+
+	return &verifier{alg: alg, verify: func(unsigned, signature string) error {
+		return VerifyED25519(unsigned, signature, edKey)
+	}}, nil
+}
+
+// rsaSignerKey makes sure a key can be used to compute RSA signatures.
+func rsaSignerKey(alg jwa.Alg, key any) (crypto.Signer, error) {
+	rsaKey, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s expects *rsa.PrivateKey or crypto.Signer, got %T", ErrInvalidKey, alg, key)
+	}
+
+	if _, ok := rsaKey.Public().(*rsa.PublicKey); !ok {
+		return nil, fmt.Errorf("%w: %s expects an RSA key, got %T", ErrInvalidKey, alg, rsaKey.Public())
+	}
+
+	return rsaKey, nil
+}
+
+// algHash returns the hash function used by a signature algorithm.
+func algHash(alg jwa.Alg) crypto.Hash {
+	switch alg {
+	case jwa.HS256, jwa.RS256, jwa.PS256, jwa.ES256:
+		return crypto.SHA256
+	case jwa.HS384, jwa.RS384, jwa.PS384, jwa.ES384:
+		return crypto.SHA384
+	case jwa.HS512, jwa.RS512, jwa.PS512, jwa.ES512:
+		return crypto.SHA512
+	default:
+		return 0
+	}
+}
+
+// checkECCurve makes sure the curve of an ECDSA key matches the one required by the algorithm.
+//
+// https://datatracker.ietf.org/doc/html/rfc7518#section-3.4
+func checkECCurve(alg jwa.Alg, curve string) error {
+	expected := map[jwa.Alg]string{
+		jwa.ES256: "P-256",
+		jwa.ES384: "P-384",
+		jwa.ES512: "P-521",
+	}[alg]
+
+	if curve != expected {
+		return fmt.Errorf("%w: %s expects curve %s, got %s", ErrInvalidKey, alg, expected, curve)
+	}
+
+	return nil
+}
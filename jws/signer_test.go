@@ -0,0 +1,181 @@
+package jwscore_test
+
+import (
+	"crypto"
+	"crypto/elliptic"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+	jwscore "github.com/a-novel-kit/jwt-core/jws"
+)
+
+func TestSignerAndVerifier(t *testing.T) {
+	hmacKey, err := jwkgen.HMAC(jwkgen.H512KeySize)
+	require.NoError(t, err)
+
+	rsaKey, err := jwkgen.RSA(jwkgen.RS256KeySize)
+	require.NoError(t, err)
+
+	ecKey256, err := jwkgen.EC(elliptic.P256())
+	require.NoError(t, err)
+
+	ecKey384, err := jwkgen.EC(elliptic.P384())
+	require.NoError(t, err)
+
+	ecKey521, err := jwkgen.EC(elliptic.P521())
+	require.NoError(t, err)
+
+	edPrivKey, edPubKey, err := jwkgen.ED25519()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		alg       jwa.Alg
+		signKey   any
+		verifyKey any
+	}{
+		{alg: jwa.HS256, signKey: hmacKey, verifyKey: hmacKey},
+		{alg: jwa.HS384, signKey: hmacKey, verifyKey: hmacKey},
+		{alg: jwa.HS512, signKey: hmacKey, verifyKey: hmacKey},
+		{alg: jwa.RS256, signKey: rsaKey, verifyKey: &rsaKey.PublicKey},
+		{alg: jwa.RS384, signKey: rsaKey, verifyKey: &rsaKey.PublicKey},
+		{alg: jwa.RS512, signKey: rsaKey, verifyKey: &rsaKey.PublicKey},
+		{alg: jwa.PS256, signKey: rsaKey, verifyKey: &rsaKey.PublicKey},
+		{alg: jwa.PS384, signKey: rsaKey, verifyKey: &rsaKey.PublicKey},
+		{alg: jwa.PS512, signKey: rsaKey, verifyKey: &rsaKey.PublicKey},
+		{alg: jwa.ES256, signKey: ecKey256, verifyKey: &ecKey256.PublicKey},
+		{alg: jwa.ES384, signKey: ecKey384, verifyKey: &ecKey384.PublicKey},
+		{alg: jwa.ES512, signKey: ecKey521, verifyKey: &ecKey521.PublicKey},
+		{alg: jwa.EdDSA, signKey: edPrivKey, verifyKey: edPubKey},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.alg), func(t *testing.T) {
+			signer, err := jwscore.NewSigner(testCase.alg, testCase.signKey)
+			require.NoError(t, err)
+			require.Equal(t, testCase.alg, signer.Alg())
+
+			verifier, err := jwscore.NewVerifier(testCase.alg, testCase.verifyKey)
+			require.NoError(t, err)
+			require.Equal(t, testCase.alg, verifier.Alg())
+
+			signature, err := signer.Sign("unsigned")
+			require.NoError(t, err)
+
+			require.NoError(t, verifier.Verify("unsigned", signature))
+			require.ErrorIs(t, verifier.Verify("tampered", signature), jwscore.ErrInvalidSignature)
+
+			// Keys of the wrong type are rejected at construction.
+			_, err = jwscore.NewSigner(testCase.alg, testCase.verifyKey)
+			if testCase.alg != jwa.HS256 && testCase.alg != jwa.HS384 && testCase.alg != jwa.HS512 {
+				require.ErrorIs(t, err, jwscore.ErrInvalidKey)
+			}
+		})
+	}
+}
+
+func TestNewSigner(t *testing.T) {
+	ecKey, err := jwkgen.EC(elliptic.P256())
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		alg jwa.Alg
+		key any
+
+		expect error
+	}{
+		{
+			name:   "unsupported algorithm",
+			alg:    "foo",
+			key:    ecKey,
+			expect: jwscore.ErrUnsupportedAlg,
+		},
+		{
+			name:   "unsecured algorithm",
+			alg:    jwa.None,
+			key:    ecKey,
+			expect: jwscore.ErrUnsupportedAlg,
+		},
+		{
+			name:   "wrong curve",
+			alg:    jwa.ES384,
+			key:    ecKey,
+			expect: jwscore.ErrInvalidKey,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := jwscore.NewSigner(testCase.alg, testCase.key)
+			require.ErrorIs(t, err, testCase.expect)
+
+			_, err = jwscore.NewVerifier(testCase.alg, &ecKey.PublicKey)
+			require.ErrorIs(t, err, testCase.expect)
+		})
+	}
+}
+
+// opaqueSigner hides the concrete type of a private key, like a key stored in an HSM or a KMS would.
+type opaqueSigner struct {
+	key crypto.Signer
+}
+
+func (s *opaqueSigner) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s *opaqueSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.key.Sign(rand, digest, opts)
+}
+
+func TestCryptoSigner(t *testing.T) {
+	rsaKey, err := jwkgen.RSA(jwkgen.RS256KeySize)
+	require.NoError(t, err)
+
+	ecKey256, err := jwkgen.EC(elliptic.P256())
+	require.NoError(t, err)
+
+	ecKey521, err := jwkgen.EC(elliptic.P521())
+	require.NoError(t, err)
+
+	_, edPubKey, err := jwkgen.ED25519()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		alg       jwa.Alg
+		signKey   crypto.Signer
+		verifyKey any
+	}{
+		{alg: jwa.RS256, signKey: &opaqueSigner{key: rsaKey}, verifyKey: &rsaKey.PublicKey},
+		{alg: jwa.PS384, signKey: &opaqueSigner{key: rsaKey}, verifyKey: &rsaKey.PublicKey},
+		{alg: jwa.ES256, signKey: &opaqueSigner{key: ecKey256}, verifyKey: &ecKey256.PublicKey},
+		{alg: jwa.ES512, signKey: &opaqueSigner{key: ecKey521}, verifyKey: &ecKey521.PublicKey},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.alg), func(t *testing.T) {
+			token, err := jwscore.Sign(jwa.JWH{Alg: testCase.alg}, []byte("payload"), testCase.signKey)
+			require.NoError(t, err)
+
+			parsed, err := jwscore.Parse(token)
+			require.NoError(t, err)
+			require.NoError(t, parsed.Verify(testCase.verifyKey))
+		})
+	}
+
+	t.Run("wrong key type", func(t *testing.T) {
+		_, err := jwscore.NewSigner(jwa.RS256, &opaqueSigner{key: ecKey256})
+		require.ErrorIs(t, err, jwscore.ErrInvalidKey)
+
+		_, err = jwscore.NewSigner(jwa.ES256, &opaqueSigner{key: rsaKey})
+		require.ErrorIs(t, err, jwscore.ErrInvalidKey)
+
+		_, err = jwscore.NewSigner(jwa.ES256, edPubKey)
+		require.ErrorIs(t, err, jwscore.ErrInvalidKey)
+	})
+}
@@ -0,0 +1,93 @@
+package jwscore
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+)
+
+var (
+	ErrInvalidHeader  = errors.New("invalid header")
+	ErrInvalidPayload = errors.New("invalid payload")
+)
+
+// b64Header is the name of the "b64" header parameter.
+const b64Header = "b64"
+
+// IsPayloadEncoded returns whether the payload of a JWS is base64url-encoded, according to the "b64" parameter
+// of its header. An error is returned if the header disables the encoding without listing "b64" as a critical
+// parameter.
+//
+// https://datatracker.ietf.org/doc/html/rfc7797#section-6
+//
+// When the "b64" value is "false", the "crit" Header Parameter MUST be
+// present, and its value MUST include "b64".
+func IsPayloadEncoded(header *jwa.JWH) (bool, error) {
+	if header.B64 == nil || *header.B64 {
+		return true, nil
+	}
+
+	if !slices.Contains(header.Crit, b64Header) {
+		return false, fmt.Errorf("%w: %q must be listed in the crit parameter", ErrInvalidHeader, b64Header)
+	}
+
+	return false, nil
+}
+
+// encodePayload returns the representation of the payload in the JWS Signing Input, and in the token.
+//
+// https://datatracker.ietf.org/doc/html/rfc7797#section-3
+//
+// When the "b64" value is "false", the payload is represented simply as the JWS Payload value; otherwise, it is
+// represented as ASCII(BASE64URL(JWS Payload)).
+func encodePayload(header *jwa.JWH, payload []byte) (string, error) {
+	encoded, err := IsPayloadEncoded(header)
+	if err != nil {
+		return "", err
+	}
+
+	if encoded {
+		return base64.RawURLEncoding.EncodeToString(payload), nil
+	}
+
+	return string(payload), nil
+}
+
+// SigningInput computes the JWS Signing Input, from the encoded protected header and the payload. The result can be
+// passed as the unsigned argument of the Sign* and Verify* functions.
+//
+// https://datatracker.ietf.org/doc/html/rfc7797#section-3
+//
+// The JWS Signing Input value is:
+//
+//	ASCII(BASE64URL(UTF8(JWS Protected Header)) || '.') || JWS Payload
+//
+// when "b64" is "false", and the default one otherwise.
+func SigningInput(header *jwa.JWH, encodedHeader string, payload []byte) (string, error) {
+	encodedPayload, err := encodePayload(header, payload)
+	if err != nil {
+		return "", err
+	}
+
+	return jwtcore.Assemble(encodedHeader, encodedPayload), nil
+}
+
+// checkCompactPayload makes sure an unencoded payload can be used in the compact serialization.
+//
+// https://datatracker.ietf.org/doc/html/rfc7797#section-5.2
+//
+// When using the JWS Compact Serialization, unencoded non-detached
+// payloads using period ('.') characters would cause parsing errors;
+// such payloads MUST NOT be used with the JWS Compact Serialization.
+func checkCompactPayload(encodedPayload string) error {
+	if strings.Contains(encodedPayload, ".") {
+		return fmt.Errorf("%w: unencoded payload must not contain '.' in the compact serialization", ErrInvalidPayload)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package jwscore_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwkgen "github.com/a-novel-kit/jwt-core/jwk/gen"
+	jwscore "github.com/a-novel-kit/jwt-core/jws"
+)
+
+func TestUnencodedPayload(t *testing.T) {
+	hmacKey, err := jwkgen.HMAC(jwkgen.H256KeySize)
+	require.NoError(t, err)
+
+	edPrivKey, edPubKey, err := jwkgen.ED25519()
+	require.NoError(t, err)
+
+	b64 := false
+	payload := []byte(`{"event":"created"}`)
+
+	t.Run("compact", func(t *testing.T) {
+		token, err := jwscore.Sign(
+			jwa.JWH{Alg: jwa.HS256, B64: &b64, Crit: []string{"b64"}}, payload, hmacKey,
+		)
+		require.NoError(t, err)
+		require.Contains(t, token, string(payload))
+
+		parsed, err := jwscore.Parse(token)
+		require.NoError(t, err)
+		require.Equal(t, payload, parsed.Payload)
+		require.NoError(t, parsed.Verify(hmacKey))
+		require.NoError(t, parsed.VerifyPayload(payload, hmacKey))
+		require.ErrorIs(t, parsed.VerifyPayload([]byte(`{"event":"deleted"}`), hmacKey), jwscore.ErrInvalidSignature)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		doc, err := jwscore.SignJSON(payload, jwscore.JSONSigner{
+			Protected: &jwa.JWH{Alg: jwa.EdDSA, B64: &b64, Crit: []string{"b64"}},
+			Key:       edPrivKey,
+		})
+		require.NoError(t, err)
+		require.Equal(t, string(payload), doc.Payload)
+
+		results, err := doc.Verify(func(_ *jwa.JWH) (any, error) { return edPubKey, nil })
+		require.NoError(t, err)
+		require.NoError(t, results[0].Err)
+
+		decoded, err := doc.DecodePayload()
+		require.NoError(t, err)
+		require.Equal(t, payload, decoded)
+	})
+
+	t.Run("missing crit", func(t *testing.T) {
+		_, err := jwscore.Sign(jwa.JWH{Alg: jwa.HS256, B64: &b64}, payload, hmacKey)
+		require.ErrorIs(t, err, jwscore.ErrInvalidHeader)
+	})
+
+	t.Run("period in compact payload", func(t *testing.T) {
+		_, err := jwscore.Sign(
+			jwa.JWH{Alg: jwa.HS256, B64: &b64, Crit: []string{"b64"}}, []byte("$.02"), hmacKey,
+		)
+		require.ErrorIs(t, err, jwscore.ErrInvalidPayload)
+	})
+
+	t.Run("unprotected b64", func(t *testing.T) {
+		_, err := jwscore.SignJSON(payload, jwscore.JSONSigner{
+			Protected: &jwa.JWH{Alg: jwa.HS256, Crit: []string{"b64"}},
+			Header:    &jwa.JWH{B64: &b64},
+			Key:       hmacKey,
+		})
+		require.ErrorIs(t, err, jwscore.ErrInvalidHeader)
+	})
+
+	t.Run("mixed b64", func(t *testing.T) {
+		_, err := jwscore.SignJSON(
+			payload,
+			jwscore.JSONSigner{Protected: &jwa.JWH{Alg: jwa.HS256, B64: &b64, Crit: []string{"b64"}}, Key: hmacKey},
+			jwscore.JSONSigner{Protected: &jwa.JWH{Alg: jwa.HS256}, Key: hmacKey},
+		)
+		require.ErrorIs(t, err, jwscore.ErrInvalidHeader)
+	})
+}
+
+func TestUnencodedPayloadVector(t *testing.T) {
+	// https://datatracker.ietf.org/doc/html/rfc7797#section-4.2
+	key, err := base64.RawURLEncoding.DecodeString(
+		"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow",
+	)
+	require.NoError(t, err)
+
+	token := "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19" +
+		"..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY"
+
+	parsed, err := jwscore.Parse(token)
+	require.NoError(t, err)
+	require.NoError(t, parsed.VerifyPayload([]byte("$.02"), key))
+
+	unsigned, err := jwscore.SigningInput(&parsed.Header, parsed.Protected, []byte("$.02"))
+	require.NoError(t, err)
+	require.Equal(t, "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19.$.02", unsigned)
+}
+
+func TestIsPayloadEncoded(t *testing.T) {
+	enabled, disabled := true, false
+
+	testCases := []struct {
+		name string
+
+		header jwa.JWH
+
+		expect    bool
+		expectErr error
+	}{
+		{
+			name:   "default",
+			header: jwa.JWH{},
+			expect: true,
+		},
+		{
+			name:   "enabled",
+			header: jwa.JWH{B64: &enabled},
+			expect: true,
+		},
+		{
+			name:   "disabled",
+			header: jwa.JWH{B64: &disabled, Crit: []string{"b64"}},
+			expect: false,
+		},
+		{
+			name:      "disabled without crit",
+			header:    jwa.JWH{B64: &disabled},
+			expectErr: jwscore.ErrInvalidHeader,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			encoded, err := jwscore.IsPayloadEncoded(&testCase.header)
+			require.ErrorIs(t, err, testCase.expectErr)
+			require.Equal(t, testCase.expect, encoded)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package jwscore
+
+import (
+	"fmt"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+	"github.com/a-novel-kit/jwt-core/jwa"
+)
+
+// SignUnsecured creates an unsecured JWS in compact serialization, using the "none" algorithm. The "alg" header is
+// set automatically, and the signature is empty, so the token has the form "header.payload.".
+//
+// Unsecured tokens provide no integrity protection. They are rejected by Parse, unless ParseConfig.AllowUnsecured
+// is set.
+//
+// https://datatracker.ietf.org/doc/html/rfc7519#section-6
+func SignUnsecured(header jwa.JWH, payload []byte) (string, error) {
+	if header.Alg != "" && header.Alg != jwa.None {
+		return "", fmt.Errorf("%w: unsecured token cannot use alg %q", ErrUnsupportedAlg, header.Alg)
+	}
+
+	header.Alg = jwa.None
+
+	encodedHeader, err := jwtcore.Encode(header)
+	if err != nil {
+		return "", fmt.Errorf("encode header: %w", err)
+	}
+
+	encodedPayload, err := encodePayload(&header, payload)
+	if err != nil {
+		return "", err
+	}
+
+	if err = checkCompactPayload(encodedPayload); err != nil {
+		return "", err
+	}
+
+	return jwtcore.Assemble(encodedHeader, encodedPayload, ""), nil
+}
+
+// VerifyUnsecured verifies the signature of an unsecured JWS, that uses the "none" algorithm.
+//
+// Other verification methods reject empty signatures, so a token stripped of its signature cannot pass as valid.
+// Unsecured tokens must be accepted explicitly, by calling this method instead.
+//
+// https://datatracker.ietf.org/doc/html/rfc7518#section-3.6
+//
+// An Unsecured JWS uses the "alg" Header Parameter value "none" and the
+// empty octet sequence as its JWS Signature value. Recipients MUST
+// verify that the JWS Signature value is the empty octet sequence.
+func VerifyUnsecured(signature string) error {
+	if signature != "" {
+		return fmt.Errorf("%w: unsecured JWS must have an empty signature", ErrInvalidSignature)
+	}
+
+	return nil
+}
+
+// VerifyUnsecured verifies an unsecured JWS. It fails unless the "alg" header is "none", and the signature is empty.
+func (token *JWS) VerifyUnsecured() error {
+	if token.Header.Alg != jwa.None {
+		return fmt.Errorf("%w: expected alg %q, got %q", ErrUnsupportedAlg, jwa.None, token.Header.Alg)
+	}
+
+	return VerifyUnsecured(token.Signature)
+}
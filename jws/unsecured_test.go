@@ -0,0 +1,81 @@
+package jwscore_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/a-novel-kit/jwt-core/jwa"
+	jwscore "github.com/a-novel-kit/jwt-core/jws"
+)
+
+func TestSignAndParseUnsecured(t *testing.T) {
+	payload := []byte(`{"iss":"joe"}`)
+
+	token, err := jwscore.SignUnsecured(jwa.JWH{Typ: "JWT"}, payload)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(token, "."))
+
+	t.Run("RejectedByDefault", func(t *testing.T) {
+		_, err := jwscore.Parse(token)
+		require.ErrorIs(t, err, jwscore.ErrUnsecuredToken)
+	})
+
+	t.Run("Allowed", func(t *testing.T) {
+		parsed, err := jwscore.ParseWithConfig(token, &jwscore.ParseConfig{AllowUnsecured: true})
+		require.NoError(t, err)
+		require.Equal(t, jwa.None, parsed.Header.Alg)
+		require.Equal(t, payload, parsed.Payload)
+		require.Empty(t, parsed.Signature)
+
+		require.NoError(t, parsed.VerifyUnsecured())
+
+		// Regular verification never accepts an unsecured token.
+		require.ErrorIs(t, parsed.Verify([]byte("secret")), jwscore.ErrMissingSignature)
+	})
+
+	t.Run("SecuredAlgorithm", func(t *testing.T) {
+		_, err := jwscore.SignUnsecured(jwa.JWH{Alg: jwa.HS256}, payload)
+		require.ErrorIs(t, err, jwscore.ErrUnsupportedAlg)
+	})
+}
+
+func TestVerifyUnsecured(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		token *jwscore.JWS
+
+		expect error
+	}{
+		{
+			name: "ok",
+
+			token: &jwscore.JWS{Header: jwa.JWH{Alg: jwa.None}},
+		},
+		{
+			name: "non empty signature",
+
+			token: &jwscore.JWS{
+				Header:    jwa.JWH{Alg: jwa.None},
+				Signature: "c2lnbmF0dXJl",
+			},
+
+			expect: jwscore.ErrInvalidSignature,
+		},
+		{
+			name: "secured algorithm",
+
+			token: &jwscore.JWS{Header: jwa.JWH{Alg: jwa.HS256}},
+
+			expect: jwscore.ErrUnsupportedAlg,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.ErrorIs(t, testCase.token.VerifyUnsecured(), testCase.expect)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package jwtcore
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// DefaultMaxTokenSize is the default maximum size, in bytes, of a serialized token.
+	DefaultMaxTokenSize = 1 << 20
+	// DefaultMaxHeaderSize is the default maximum size, in bytes, of an encoded protected header.
+	DefaultMaxHeaderSize = 32 << 10
+	// DefaultMaxPayloadSize is the default maximum size, in bytes, of an encoded payload (or ciphertext, for a JWE).
+	DefaultMaxPayloadSize = DefaultMaxTokenSize
+)
+
+var (
+	ErrLimitExceeded   = errors.New("token exceeds the size limits")
+	ErrTooManySegments = errors.New("token has too many segments")
+)
+
+// Limits bounds the size of a token. They are checked on the serialized token, before anything is decoded, so a
+// hostile token cannot force large allocations.
+type Limits struct {
+	// MaxTokenSize is the maximum size, in bytes, of the serialized token. If zero, DefaultMaxTokenSize is used.
+	MaxTokenSize int
+	// MaxHeaderSize is the maximum size, in bytes, of the encoded protected header. If zero,
+	// DefaultMaxHeaderSize is used.
+	MaxHeaderSize int
+	// MaxPayloadSize is the maximum size, in bytes, of the encoded payload, or of the encoded ciphertext for a
+	// JWE. If zero, DefaultMaxPayloadSize is used.
+	MaxPayloadSize int
+}
+
+// CheckTokenSize makes sure the size of a serialized token does not exceed the limit. A nil Limits uses the
+// defaults.
+func (limits *Limits) CheckTokenSize(size int) error {
+	return checkLimit("token", size, limits.get().MaxTokenSize, DefaultMaxTokenSize)
+}
+
+// CheckHeaderSize makes sure the size of an encoded protected header does not exceed the limit. A nil Limits uses
+// the defaults.
+func (limits *Limits) CheckHeaderSize(size int) error {
+	return checkLimit("header", size, limits.get().MaxHeaderSize, DefaultMaxHeaderSize)
+}
+
+// CheckPayloadSize makes sure the size of an encoded payload does not exceed the limit. A nil Limits uses the
+// defaults.
+func (limits *Limits) CheckPayloadSize(size int) error {
+	return checkLimit("payload", size, limits.get().MaxPayloadSize, DefaultMaxPayloadSize)
+}
+
+func (limits *Limits) get() Limits {
+	if limits == nil {
+		return Limits{}
+	}
+
+	return *limits
+}
+
+func checkLimit(name string, size, limit, defaultLimit int) error {
+	if limit == 0 {
+		limit = defaultLimit
+	}
+
+	if size > limit {
+		return fmt.Errorf("%w: %s is %d bytes long, the limit is %d", ErrLimitExceeded, name, size, limit)
+	}
+
+	return nil
+}
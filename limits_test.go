@@ -0,0 +1,67 @@
+package jwtcore_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	jwtcore "github.com/a-novel-kit/jwt-core"
+)
+
+func TestLimits(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		limits *jwtcore.Limits
+		check  func(limits *jwtcore.Limits) error
+
+		expect error
+	}{
+		{
+			name:   "default token size",
+			check:  func(limits *jwtcore.Limits) error { return limits.CheckTokenSize(jwtcore.DefaultMaxTokenSize) },
+			expect: nil,
+		},
+		{
+			name:   "default token size exceeded",
+			check:  func(limits *jwtcore.Limits) error { return limits.CheckTokenSize(jwtcore.DefaultMaxTokenSize + 1) },
+			expect: jwtcore.ErrLimitExceeded,
+		},
+		{
+			name:   "default header size exceeded",
+			check:  func(limits *jwtcore.Limits) error { return limits.CheckHeaderSize(jwtcore.DefaultMaxHeaderSize + 1) },
+			expect: jwtcore.ErrLimitExceeded,
+		},
+		{
+			name:   "default payload size exceeded",
+			check:  func(limits *jwtcore.Limits) error { return limits.CheckPayloadSize(jwtcore.DefaultMaxPayloadSize + 1) },
+			expect: jwtcore.ErrLimitExceeded,
+		},
+		{
+			name:   "custom token size",
+			limits: &jwtcore.Limits{MaxTokenSize: 10},
+			check:  func(limits *jwtcore.Limits) error { return limits.CheckTokenSize(11) },
+			expect: jwtcore.ErrLimitExceeded,
+		},
+		{
+			name:   "custom header size",
+			limits: &jwtcore.Limits{MaxHeaderSize: 10},
+			check:  func(limits *jwtcore.Limits) error { return limits.CheckHeaderSize(11) },
+			expect: jwtcore.ErrLimitExceeded,
+		},
+		{
+			name:   "custom payload size",
+			limits: &jwtcore.Limits{MaxPayloadSize: 2 * jwtcore.DefaultMaxPayloadSize},
+			check: func(limits *jwtcore.Limits) error {
+				return limits.CheckPayloadSize(jwtcore.DefaultMaxPayloadSize + 1)
+			},
+			expect: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.ErrorIs(t, testCase.check(testCase.limits), testCase.expect)
+		})
+	}
+}